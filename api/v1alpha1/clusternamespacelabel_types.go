@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceSelector selects namespaces by their labels and names. A namespace is selected when it
+// matches the label selector and one of the names. At least one of them must be set, so that an omitted
+// field never selects every namespace of the cluster; an empty labelSelector selects every namespace.
+// +kubebuilder:validation:XValidation:rule="has(self.labelSelector) || (has(self.names) && size(self.names) > 0)",message="at least one of labelSelector or names must be set"
+type NamespaceSelector struct {
+	// LabelSelector selects namespaces by their labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Names selects namespaces by their names. Entries may be globs using '*' and '?'.
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	Names []string `json:"names,omitempty"`
+}
+
+// ClusterNamespaceLabelSpec defines the desired state of ClusterNamespaceLabel
+type ClusterNamespaceLabelSpec struct {
+	// NamespaceSelector selects the namespaces the labels are applied to.
+	NamespaceSelector NamespaceSelector `json:"namespaceSelector"`
+
+	// +kubebuilder:doc:note="This field contains labels that will be applied to the selected namespaces. System-reserved labels like 'kubernetes.io/' are not allowed."
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are applied to the selected namespaces like labels.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Priority resolves conflicts with other NamespaceLabels and ClusterNamespaceLabels selecting the same
+	// namespace when the controller runs with the Priority conflict strategy.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// ClusterNamespaceLabelStatus defines the observed state of ClusterNamespaceLabel
+type ClusterNamespaceLabelStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	LastSyncedTimeStamp *metav1.Time `json:"lastSyncedTimeStamp,omitempty"`
+
+	// MatchedNamespaces contains the names of the namespaces selected by the ClusterNamespaceLabel.
+	// +listType=set
+	MatchedNamespaces []string `json:"matchedNamespaces,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Reasons used for ClusterNamespaceLabel conditions.
+const (
+	ReasonInvalidSelector = "InvalidSelector"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=all-labels
+
+// ClusterNamespaceLabel is the Schema for the clusternamespacelabels API
+type ClusterNamespaceLabel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterNamespaceLabelSpec   `json:"spec,omitempty"`
+	Status ClusterNamespaceLabelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterNamespaceLabelList contains a list of ClusterNamespaceLabel
+type ClusterNamespaceLabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterNamespaceLabel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterNamespaceLabel{}, &ClusterNamespaceLabelList{})
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/oshribelay/namespace-label/api/v1beta1"
+)
+
+// ConvertTo converts this NamespaceLabel to the Hub version (v1beta1). The labels and annotations become
+// entries sorted by key. A KeyDeletionPoliciesAnnotation is moved to the deletionPolicy of the entries when
+// it is exactly the annotation ConvertFrom would write back, otherwise it is kept as is. A
+// ConvertedKeyDeletionPoliciesAnnotation still matching the KeyDeletionPoliciesAnnotation restores the
+// annotations and the deletionPolicy of the entries of the v1beta1 NamespaceLabel. It is dropped once it no
+// longer matches, a ConvertedKeyDeletionPoliciesAnnotation that was not written by ConvertFrom is kept as is.
+func (src *NamespaceLabel) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.NamespaceLabel)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	var policies KeyDeletionPolicies
+	converted, recorded, ok := src.convertedKeyDeletionPolicies()
+	if recorded {
+		delete(dst.Annotations, ConvertedKeyDeletionPoliciesAnnotation)
+	}
+	if ok {
+		if converted.Annotation != nil {
+			dst.Annotations[KeyDeletionPoliciesAnnotation] = *converted.Annotation
+		} else {
+			delete(dst.Annotations, KeyDeletionPoliciesAnnotation)
+		}
+		if converted.Converted != nil {
+			dst.Annotations[ConvertedKeyDeletionPoliciesAnnotation] = *converted.Converted
+		}
+		policies = converted.KeyDeletionPolicies
+	} else if value, exists := src.Annotations[KeyDeletionPoliciesAnnotation]; exists {
+		if parsed, ok := entryKeyDeletionPolicies(value, src.Spec); ok {
+			policies = parsed
+			delete(dst.Annotations, KeyDeletionPoliciesAnnotation)
+		}
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	dst.Spec = v1beta1.NamespaceLabelSpec{
+		Labels:         labelEntries(src.Spec.Labels, policies.Labels),
+		Annotations:    labelEntries(src.Spec.Annotations, policies.Annotations),
+		Priority:       src.Spec.Priority,
+		ValidationMode: v1beta1.ValidationMode(src.Spec.ValidationMode),
+		DryRun:         src.Spec.DryRun,
+		Suspend:        src.Spec.Suspend,
+		DeletionPolicy: v1beta1.DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+
+	dst.Status = v1beta1.NamespaceLabelStatus{
+		ObservedGeneration:  src.Status.ObservedGeneration,
+		LastSyncedTimeStamp: src.Status.LastSyncedTimeStamp.DeepCopy(),
+		AppliedLabels:       copyMap(src.Status.AppliedLabels),
+		RejectedLabels:      convertSlice(src.Status.RejectedLabels, func(r RejectedLabel) v1beta1.RejectedLabel { return v1beta1.RejectedLabel(r) }),
+		AppliedLabelCount:   src.Status.AppliedLabelCount,
+		RejectedLabelCount:  src.Status.RejectedLabelCount,
+		AppliedAnnotations:  copyMap(src.Status.AppliedAnnotations),
+		RejectedAnnotations: convertSlice(src.Status.RejectedAnnotations, func(r RejectedLabel) v1beta1.RejectedLabel { return v1beta1.RejectedLabel(r) }),
+		Conditions:          append(src.Status.Conditions[:0:0], src.Status.Conditions...),
+	}
+	if src.Status.Plan != nil {
+		dst.Status.Plan = &v1beta1.NamespaceLabelPlan{
+			Labels: v1beta1.KeyChanges{
+				Added:   copyMap(src.Status.Plan.Labels.Added),
+				Changed: copyMap(src.Status.Plan.Labels.Changed),
+				Removed: append(src.Status.Plan.Labels.Removed[:0:0], src.Status.Plan.Labels.Removed...),
+			},
+			Annotations: v1beta1.KeyChanges{
+				Added:   copyMap(src.Status.Plan.Annotations.Added),
+				Changed: copyMap(src.Status.Plan.Annotations.Changed),
+				Removed: append(src.Status.Plan.Annotations.Removed[:0:0], src.Status.Plan.Annotations.Removed...),
+			},
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version. The deletionPolicy of the entries is
+// written to the KeyDeletionPoliciesAnnotation. When the v1beta1 NamespaceLabel has a
+// KeyDeletionPoliciesAnnotation too, the deletionPolicy of the entries is merged into it and both are
+// recorded in the ConvertedKeyDeletionPoliciesAnnotation for ConvertTo. A ConvertedKeyDeletionPoliciesAnnotation
+// of the v1beta1 NamespaceLabel is recorded too when it would be mistaken for one written by ConvertFrom.
+func (dst *NamespaceLabel) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.NamespaceLabel)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	labels, labelPolicies := labelMap(src.Spec.Labels)
+	annotations, annotationPolicies := labelMap(src.Spec.Annotations)
+	dst.Spec = NamespaceLabelSpec{
+		Labels:         labels,
+		Annotations:    annotations,
+		Priority:       src.Spec.Priority,
+		ValidationMode: ValidationMode(src.Spec.ValidationMode),
+		DryRun:         src.Spec.DryRun,
+		Suspend:        src.Spec.Suspend,
+		DeletionPolicy: DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+
+	converted := convertedKeyDeletionPolicies{
+		KeyDeletionPolicies: KeyDeletionPolicies{Labels: labelPolicies, Annotations: annotationPolicies},
+	}
+	record := false
+	if value, exists := src.Annotations[KeyDeletionPoliciesAnnotation]; exists {
+		converted.Annotation = &value
+		merged := mergeKeyDeletionPolicies(value, converted.KeyDeletionPolicies)
+		dst.Annotations[KeyDeletionPoliciesAnnotation] = merged
+		// without the entry policies, ConvertTo keeps the annotation as is unless it could be moved to the entries
+		_, moved := entryKeyDeletionPolicies(merged, dst.Spec)
+		record = moved || len(labelPolicies) > 0 || len(annotationPolicies) > 0
+	} else if encoded, ok := encodeKeyDeletionPolicies(converted.KeyDeletionPolicies); ok {
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string, 1)
+		}
+		dst.Annotations[KeyDeletionPoliciesAnnotation] = encoded
+	}
+	if value, exists := src.Annotations[ConvertedKeyDeletionPoliciesAnnotation]; exists {
+		// ConvertTo only keeps the annotation of the hub as is when it was not written by ConvertFrom
+		if _, err := parseConvertedKeyDeletionPolicies(value); record || err == nil {
+			converted.Converted = &value
+			record = true
+		}
+	}
+	if record {
+		encoded, err := json.Marshal(converted)
+		if err != nil {
+			return err
+		}
+		dst.Annotations[ConvertedKeyDeletionPoliciesAnnotation] = string(encoded)
+	}
+
+	dst.Status = NamespaceLabelStatus{
+		ObservedGeneration:  src.Status.ObservedGeneration,
+		LastSyncedTimeStamp: src.Status.LastSyncedTimeStamp.DeepCopy(),
+		AppliedLabels:       copyMap(src.Status.AppliedLabels),
+		RejectedLabels:      convertSlice(src.Status.RejectedLabels, func(r v1beta1.RejectedLabel) RejectedLabel { return RejectedLabel(r) }),
+		AppliedLabelCount:   src.Status.AppliedLabelCount,
+		RejectedLabelCount:  src.Status.RejectedLabelCount,
+		AppliedAnnotations:  copyMap(src.Status.AppliedAnnotations),
+		RejectedAnnotations: convertSlice(src.Status.RejectedAnnotations, func(r v1beta1.RejectedLabel) RejectedLabel { return RejectedLabel(r) }),
+		Conditions:          append(src.Status.Conditions[:0:0], src.Status.Conditions...),
+	}
+	if src.Status.Plan != nil {
+		dst.Status.Plan = &NamespaceLabelPlan{
+			Labels: KeyChanges{
+				Added:   copyMap(src.Status.Plan.Labels.Added),
+				Changed: copyMap(src.Status.Plan.Labels.Changed),
+				Removed: append(src.Status.Plan.Labels.Removed[:0:0], src.Status.Plan.Labels.Removed...),
+			},
+			Annotations: KeyChanges{
+				Added:   copyMap(src.Status.Plan.Annotations.Added),
+				Changed: copyMap(src.Status.Plan.Annotations.Changed),
+				Removed: append(src.Status.Plan.Annotations.Removed[:0:0], src.Status.Plan.Annotations.Removed...),
+			},
+		}
+	}
+	return nil
+}
+
+// convertedKeyDeletionPolicies is the value of the ConvertedKeyDeletionPoliciesAnnotation.
+type convertedKeyDeletionPolicies struct {
+	// Annotation is the KeyDeletionPoliciesAnnotation of the v1beta1 NamespaceLabel, nil when it had none.
+	Annotation *string `json:"annotation,omitempty"`
+	// Converted is the ConvertedKeyDeletionPoliciesAnnotation of the v1beta1 NamespaceLabel, nil when it had none.
+	Converted *string `json:"converted,omitempty"`
+	// KeyDeletionPolicies are the deletionPolicy of the entries of the v1beta1 NamespaceLabel.
+	KeyDeletionPolicies `json:",inline"`
+}
+
+// parseConvertedKeyDeletionPolicies parses a ConvertedKeyDeletionPoliciesAnnotation written by ConvertFrom.
+func parseConvertedKeyDeletionPolicies(value string) (convertedKeyDeletionPolicies, error) {
+	var converted convertedKeyDeletionPolicies
+	if err := json.Unmarshal([]byte(value), &converted); err != nil {
+		return convertedKeyDeletionPolicies{}, err
+	}
+	policies, err := parseKeyDeletionPolicies(value)
+	if err != nil {
+		return convertedKeyDeletionPolicies{}, err
+	}
+	converted.KeyDeletionPolicies = policies
+	return converted, nil
+}
+
+// convertedKeyDeletionPolicies returns the ConvertedKeyDeletionPoliciesAnnotation. recorded is false when it
+// is not set or was not written by ConvertFrom, ok is false when it is not recorded, or when the labels,
+// annotations or KeyDeletionPoliciesAnnotation changed since it was written.
+func (n *NamespaceLabel) convertedKeyDeletionPolicies() (converted convertedKeyDeletionPolicies, recorded, ok bool) {
+	value, exists := n.Annotations[ConvertedKeyDeletionPoliciesAnnotation]
+	if !exists {
+		return convertedKeyDeletionPolicies{}, false, false
+	}
+	converted, err := parseConvertedKeyDeletionPolicies(value)
+	if err != nil {
+		return convertedKeyDeletionPolicies{}, false, false
+	}
+	encoded, _ := encodeKeyDeletionPolicies(converted.KeyDeletionPolicies)
+	if valid, _ := encodeKeyDeletionPolicies(KeyDeletionPolicies{
+		Labels:      keyPoliciesOf(converted.Labels, n.Spec.Labels),
+		Annotations: keyPoliciesOf(converted.Annotations, n.Spec.Annotations),
+	}); valid != encoded {
+		return convertedKeyDeletionPolicies{}, true, false
+	}
+	var original string
+	if converted.Annotation != nil {
+		original = *converted.Annotation
+	}
+	merged := mergeKeyDeletionPolicies(original, converted.KeyDeletionPolicies)
+	annotation, exists := n.Annotations[KeyDeletionPoliciesAnnotation]
+	if exists != (converted.Annotation != nil || merged != "") || annotation != merged {
+		return convertedKeyDeletionPolicies{}, true, false
+	}
+	return converted, true, true
+}
+
+// entryKeyDeletionPolicies returns the policies of a KeyDeletionPoliciesAnnotation, ok is true when the
+// annotation is exactly the one ConvertFrom writes for the entries of spec with these policies.
+func entryKeyDeletionPolicies(value string, spec NamespaceLabelSpec) (KeyDeletionPolicies, bool) {
+	parsed, err := parseKeyDeletionPolicies(value)
+	if err != nil {
+		return KeyDeletionPolicies{}, false
+	}
+	parsed = KeyDeletionPolicies{
+		Labels:      keyPoliciesOf(parsed.Labels, spec.Labels),
+		Annotations: keyPoliciesOf(parsed.Annotations, spec.Annotations),
+	}
+	if encoded, ok := encodeKeyDeletionPolicies(parsed); !ok || encoded != value {
+		return KeyDeletionPolicies{}, false
+	}
+	return parsed, true
+}
+
+// mergeKeyDeletionPolicies returns the KeyDeletionPoliciesAnnotation value with policies set on top of it.
+// The value is returned as is when there are no policies, and replaced when it is not valid.
+func mergeKeyDeletionPolicies(value string, policies KeyDeletionPolicies) string {
+	if len(policies.Labels) == 0 && len(policies.Annotations) == 0 {
+		return value
+	}
+	merged, err := parseKeyDeletionPolicies(value)
+	if err != nil {
+		merged = KeyDeletionPolicies{}
+	}
+	merge := func(into, from map[string]DeletionPolicy) map[string]DeletionPolicy {
+		for key, policy := range from {
+			if into == nil {
+				into = make(map[string]DeletionPolicy, len(from))
+			}
+			into[key] = policy
+		}
+		return into
+	}
+	merged.Labels = merge(merged.Labels, policies.Labels)
+	merged.Annotations = merge(merged.Annotations, policies.Annotations)
+	encoded, _ := encodeKeyDeletionPolicies(merged)
+	return encoded
+}
+
+// labelEntries converts labels or annotations to entries sorted by key, with their deletion policy.
+func labelEntries(values map[string]string, policies map[string]DeletionPolicy) []v1beta1.LabelEntry {
+	if values == nil {
+		return nil
+	}
+	entries := make([]v1beta1.LabelEntry, 0, len(values))
+	for key, value := range values {
+		entries = append(entries, v1beta1.LabelEntry{
+			Key:            key,
+			Value:          value,
+			DeletionPolicy: v1beta1.KeyDeletionPolicy(policies[key]),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// labelMap converts entries to labels or annotations, together with the deletion policies of the entries
+// that have one.
+func labelMap(entries []v1beta1.LabelEntry) (map[string]string, map[string]DeletionPolicy) {
+	if entries == nil {
+		return nil, nil
+	}
+	values := make(map[string]string, len(entries))
+	var policies map[string]DeletionPolicy
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+		if entry.DeletionPolicy != "" {
+			if policies == nil {
+				policies = make(map[string]DeletionPolicy)
+			}
+			policies[entry.Key] = DeletionPolicy(entry.DeletionPolicy)
+		}
+	}
+	return values, policies
+}
+
+// keyPoliciesOf returns the non empty policies of the keys of values.
+func keyPoliciesOf(policies map[string]DeletionPolicy, values map[string]string) map[string]DeletionPolicy {
+	var kept map[string]DeletionPolicy
+	for key, policy := range policies {
+		if _, exists := values[key]; !exists || policy == "" {
+			continue
+		}
+		if kept == nil {
+			kept = make(map[string]DeletionPolicy)
+		}
+		kept[key] = policy
+	}
+	return kept
+}
+
+// encodeKeyDeletionPolicies returns the value of the KeyDeletionPoliciesAnnotation for policies, ok is
+// false when there are no policies.
+func encodeKeyDeletionPolicies(policies KeyDeletionPolicies) (string, bool) {
+	if len(policies.Labels) == 0 && len(policies.Annotations) == 0 {
+		return "", false
+	}
+	encoded, err := json.Marshal(policies)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+func copyMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+func convertSlice[S, D any](items []S, convert func(S) D) []D {
+	if items == nil {
+		return nil
+	}
+	converted := make([]D, 0, len(items))
+	for _, item := range items {
+		converted = append(converted, convert(item))
+	}
+	return converted
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apitestingfuzzer "k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/oshribelay/namespace-label/api/v1beta1"
+)
+
+const fuzzIterations = 1000
+
+// conversionFuzzerFuncs restricts the fuzzed objects to the ones a real API server can hold: the entries of a
+// v1beta1 NamespaceLabel have unique keys, a supported deletion policy, and are sorted like the entries
+// converted from v1alpha1 maps. v1alpha1 NamespaceLabels get a valid KeyDeletionPoliciesAnnotation half of the time,
+// v1beta1 NamespaceLabels get a KeyDeletionPoliciesAnnotation, valid or not, half of the time. Both get a
+// ConvertedKeyDeletionPoliciesAnnotation set by hand a quarter of the time, which v1beta1 NamespaceLabels set
+// to a value ConvertFrom could have written half of the time.
+func conversionFuzzerFuncs(_ serializer.CodecFactory) []interface{} {
+	keyPolicies := []v1beta1.KeyDeletionPolicy{"", v1beta1.KeyDeletionPolicyDelete, v1beta1.KeyDeletionPolicyRetain}
+	fuzzEntries := func(entries []v1beta1.LabelEntry, c fuzz.Continue) []v1beta1.LabelEntry {
+		if entries == nil {
+			return nil
+		}
+		seen := make(map[string]bool, len(entries))
+		unique := make([]v1beta1.LabelEntry, 0, len(entries))
+		for _, entry := range entries {
+			if seen[entry.Key] {
+				continue
+			}
+			seen[entry.Key] = true
+			entry.DeletionPolicy = keyPolicies[c.Intn(len(keyPolicies))]
+			unique = append(unique, entry)
+		}
+		sort.Slice(unique, func(i, j int) bool { return unique[i].Key < unique[j].Key })
+		return unique
+	}
+	fuzzConverted := func(annotations map[string]string, recordable bool, c fuzz.Continue) map[string]string {
+		if c.Intn(4) != 0 {
+			return annotations
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[ConvertedKeyDeletionPoliciesAnnotation] = c.RandString()
+		if recordable && c.RandBool() {
+			// matches the converted NamespaceLabel when the hub has no entry policies
+			recorded, exists := annotations[KeyDeletionPoliciesAnnotation]
+			if !exists {
+				recorded = c.RandString()
+			}
+			encoded, _ := json.Marshal(convertedKeyDeletionPolicies{Annotation: &recorded})
+			annotations[ConvertedKeyDeletionPoliciesAnnotation] = string(encoded)
+		}
+		return annotations
+	}
+	fuzzPolicies := func(values map[string]string, c fuzz.Continue) map[string]DeletionPolicy {
+		policies := make(map[string]DeletionPolicy)
+		for key := range values {
+			if c.RandBool() {
+				policies[key] = []DeletionPolicy{DeletionPolicyDelete, DeletionPolicyRetain}[c.Intn(2)]
+			}
+		}
+		return policies
+	}
+
+	return []interface{}{
+		func(spec *v1beta1.NamespaceLabelSpec, c fuzz.Continue) {
+			c.FuzzNoCustom(spec)
+			spec.Labels = fuzzEntries(spec.Labels, c)
+			spec.Annotations = fuzzEntries(spec.Annotations, c)
+		},
+		func(nsLabel *v1beta1.NamespaceLabel, c fuzz.Continue) {
+			c.FuzzNoCustom(nsLabel)
+			defer func() {
+				nsLabel.Annotations = fuzzConverted(nsLabel.Annotations, true, c)
+			}()
+			if !c.RandBool() {
+				return
+			}
+			if nsLabel.Annotations == nil {
+				nsLabel.Annotations = make(map[string]string)
+			}
+			if c.RandBool() {
+				nsLabel.Annotations[KeyDeletionPoliciesAnnotation] = c.RandString()
+				return
+			}
+			labels, _ := labelMap(nsLabel.Spec.Labels)
+			annotations, _ := labelMap(nsLabel.Spec.Annotations)
+			policies := KeyDeletionPolicies{
+				Labels:      fuzzPolicies(labels, c),
+				Annotations: fuzzPolicies(annotations, c),
+			}
+			if c.RandBool() {
+				policies.Labels[c.RandString()] = DeletionPolicyRetain
+			}
+			encoded, _ := encodeKeyDeletionPolicies(policies)
+			nsLabel.Annotations[KeyDeletionPoliciesAnnotation] = encoded
+		},
+		func(nsLabel *NamespaceLabel, c fuzz.Continue) {
+			c.FuzzNoCustom(nsLabel)
+			nsLabel.Annotations = fuzzConverted(nsLabel.Annotations, false, c)
+			if !c.RandBool() {
+				return
+			}
+			policies := KeyDeletionPolicies{
+				Labels:      fuzzPolicies(nsLabel.Spec.Labels, c),
+				Annotations: fuzzPolicies(nsLabel.Spec.Annotations, c),
+			}
+			if encoded, ok := encodeKeyDeletionPolicies(policies); ok {
+				if nsLabel.Annotations == nil {
+					nsLabel.Annotations = make(map[string]string)
+				}
+				nsLabel.Annotations[KeyDeletionPoliciesAnnotation] = encoded
+			}
+		},
+	}
+}
+
+var _ = Describe("NamespaceLabel conversion", func() {
+	scheme := runtime.NewScheme()
+	Expect(AddToScheme(scheme)).To(Succeed())
+	Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+
+	var fuzzer *fuzz.Fuzzer
+
+	BeforeEach(func() {
+		fuzzer = apitestingfuzzer.FuzzerFor(
+			apitestingfuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, conversionFuzzerFuncs),
+			rand.NewSource(GinkgoRandomSeed()),
+			serializer.NewCodecFactory(scheme),
+		)
+	})
+
+	It("should round-trip v1alpha1 through the v1beta1 hub losslessly", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			spoke := &NamespaceLabel{}
+			fuzzer.Fuzz(spoke)
+
+			hub := &v1beta1.NamespaceLabel{}
+			Expect(spoke.DeepCopy().ConvertTo(hub)).To(Succeed())
+			restored := &NamespaceLabel{}
+			Expect(restored.ConvertFrom(hub)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(spoke, restored)).To(BeTrue(),
+				"v1alpha1 NamespaceLabel changed by the round trip:\n%s\n%s", dump.Pretty(spoke), dump.Pretty(restored))
+		}
+	})
+
+	It("should round-trip the v1beta1 hub through v1alpha1 losslessly", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			hub := &v1beta1.NamespaceLabel{}
+			fuzzer.Fuzz(hub)
+
+			spoke := &NamespaceLabel{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+			restored := &v1beta1.NamespaceLabel{}
+			Expect(spoke.ConvertTo(restored)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(hub, restored)).To(BeTrue(),
+				"v1beta1 NamespaceLabel changed by the round trip:\n%s\n%s", dump.Pretty(hub), dump.Pretty(restored))
+		}
+	})
+
+	It("should move the key deletion policies annotation to the label entries", func() {
+		spoke := &NamespaceLabel{}
+		spoke.Annotations = map[string]string{KeyDeletionPoliciesAnnotation: `{"labels":{"team":"Retain"}}`}
+		spoke.Spec.Labels = map[string]string{"team": "a", "env": "dev"}
+
+		hub := &v1beta1.NamespaceLabel{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).NotTo(HaveKey(KeyDeletionPoliciesAnnotation))
+		Expect(hub.Spec.Labels).To(Equal([]v1beta1.LabelEntry{
+			{Key: "env", Value: "dev"},
+			{Key: "team", Value: "a", DeletionPolicy: v1beta1.KeyDeletionPolicyRetain},
+		}))
+
+		By("keeping an annotation that does not match the labels as is")
+		spoke.Annotations[KeyDeletionPoliciesAnnotation] = `{"labels":{"missing":"Retain"}}`
+		hub = &v1beta1.NamespaceLabel{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).To(HaveKeyWithValue(KeyDeletionPoliciesAnnotation, `{"labels":{"missing":"Retain"}}`))
+		Expect(hub.Spec.Labels).To(HaveEach(HaveField("DeletionPolicy", BeEmpty())))
+
+		By("keeping a converted key deletion policies annotation set by hand as is")
+		spoke.Annotations[ConvertedKeyDeletionPoliciesAnnotation] = "set by hand"
+		hub = &v1beta1.NamespaceLabel{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).To(HaveKeyWithValue(ConvertedKeyDeletionPoliciesAnnotation, "set by hand"))
+	})
+
+	It("should merge the deletion policies of the entries into a key deletion policies annotation of the hub", func() {
+		hub := &v1beta1.NamespaceLabel{}
+		hub.Annotations = map[string]string{KeyDeletionPoliciesAnnotation: `{"labels":{"env":"Retain","team":"Retain"}}`}
+		hub.Spec.Labels = []v1beta1.LabelEntry{
+			{Key: "env", Value: "dev"},
+			{Key: "team", Value: "a", DeletionPolicy: v1beta1.KeyDeletionPolicyDelete},
+		}
+
+		spoke := &NamespaceLabel{}
+		Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+		Expect(spoke.Annotations).To(HaveKeyWithValue(KeyDeletionPoliciesAnnotation, `{"labels":{"env":"Retain","team":"Delete"}}`))
+		policies, err := spoke.KeyDeletionPolicies()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies.Labels).To(Equal(map[string]DeletionPolicy{"env": DeletionPolicyRetain, "team": DeletionPolicyDelete}))
+
+		By("restoring the annotation and the entries of the hub")
+		restored := &v1beta1.NamespaceLabel{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Annotations).To(Equal(hub.Annotations))
+		Expect(restored.Spec.Labels).To(Equal(hub.Spec.Labels))
+
+		By("ignoring the recorded hub annotation once the key deletion policies annotation changed")
+		spoke.Annotations[KeyDeletionPoliciesAnnotation] = `{"labels":{"env":"Delete"}}`
+		restored = &v1beta1.NamespaceLabel{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Annotations).To(BeNil())
+		Expect(restored.Spec.Labels).To(Equal([]v1beta1.LabelEntry{
+			{Key: "env", Value: "dev", DeletionPolicy: v1beta1.KeyDeletionPolicyDelete},
+			{Key: "team", Value: "a"},
+		}))
+	})
+})
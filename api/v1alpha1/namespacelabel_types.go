@@ -17,24 +17,272 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ValidationMode decides how a NamespaceLabel with invalid labels or annotations is applied.
+// +kubebuilder:validation:Enum=Strict;BestEffort
+type ValidationMode string
+
+const (
+	// ValidationModeStrict applies none of the changes to the NamespaceLabel while any of its labels or
+	// annotations is invalid, the labels and annotations applied last are kept on the namespace.
+	ValidationModeStrict ValidationMode = "Strict"
+	// ValidationModeBestEffort applies the valid labels and annotations and skips the invalid ones.
+	ValidationModeBestEffort ValidationMode = "BestEffort"
+)
+
+// ForceFinalizeAnnotation, set to "true" on a NamespaceLabel being deleted, removes its finalizer without
+// loading the protected labels or updating the namespace. It is the escape hatch for NamespaceLabels whose
+// labels cannot be released, the labels they applied stay on the namespace and must be cleaned up by hand.
+const ForceFinalizeAnnotation = "namespacelabel.dana.io/force-finalize"
+
+// DeletionPolicy decides what happens to the labels of a NamespaceLabel when it is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the labels and annotations from the namespace.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the labels and annotations on the namespace and stops managing them.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves every label and annotation on the namespace and stops managing them,
+	// ignoring the deletion policies set on individual keys.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// KeyDeletionPoliciesAnnotation overrides the DeletionPolicy of individual labels and annotations of a
+// NamespaceLabel. Its value is a JSON KeyDeletionPolicies object, e.g. {"labels":{"team":"Retain"}}. It holds
+// the deletionPolicy of the label entries of the v1beta1 API.
+const KeyDeletionPoliciesAnnotation = "namespacelabel.dana.io/key-deletion-policies"
+
+// ConvertedKeyDeletionPoliciesAnnotation is reserved for the conversion from v1beta1. It records the
+// KeyDeletionPoliciesAnnotation a v1beta1 NamespaceLabel was written with, and the deletionPolicy of its
+// entries, when they are merged into the KeyDeletionPoliciesAnnotation of v1alpha1. A value that was not
+// written by the conversion is left untouched.
+const ConvertedKeyDeletionPoliciesAnnotation = "namespacelabel.dana.io/converted-key-deletion-policies"
+
+// KeyDeletionPolicies maps labels and annotations to the DeletionPolicy, Delete or Retain, overriding the
+// DeletionPolicy of their NamespaceLabel.
+type KeyDeletionPolicies struct {
+	Labels      map[string]DeletionPolicy `json:"labels,omitempty"`
+	Annotations map[string]DeletionPolicy `json:"annotations,omitempty"`
+}
+
 // NamespaceLabelSpec defines the desired state of NamespaceLabel
 type NamespaceLabelSpec struct {
 	// +kubebuilder:doc:note="This field contains labels that will be applied to the namespace. System-reserved labels like 'kubernetes.io/' are not allowed."
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are applied to the namespace like labels. Protected annotations are configured with
+	// ProtectedLabelPolicies targeting annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Priority resolves conflicts with other NamespaceLabels in the same namespace when the controller
+	// runs with the Priority conflict strategy. The NamespaceLabel with the highest priority wins.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// ValidationMode decides whether the valid labels and annotations are applied while some of them
+	// are rejected. Rejected keys are reported in the status either way.
+	// +kubebuilder:default=Strict
+	// +optional
+	ValidationMode ValidationMode `json:"validationMode,omitempty"`
+
+	// DryRun computes the changes the NamespaceLabel would make to the namespace and reports them in
+	// status.plan and in events, without writing them to the namespace. The labels applied before the
+	// NamespaceLabel became a dry run stay on the namespace.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Suspend stops the reconciliation of the NamespaceLabel. The labels it applied last are kept on the
+	// namespace, but changes to the spec are not applied and labels modified on the namespace are not restored.
+	// Deleting a suspended NamespaceLabel still removes its labels.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DeletionPolicy decides whether the labels and annotations are removed from the namespace when the
+	// NamespaceLabel is deleted.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// KeyDeletionPolicies returns the key deletion policies set by the KeyDeletionPoliciesAnnotation, they
+// are empty when the annotation is not set.
+func (n *NamespaceLabel) KeyDeletionPolicies() (KeyDeletionPolicies, error) {
+	value, exists := n.Annotations[KeyDeletionPoliciesAnnotation]
+	if !exists {
+		return KeyDeletionPolicies{}, nil
+	}
+	return parseKeyDeletionPolicies(value)
+}
+
+// parseKeyDeletionPolicies parses the value of a KeyDeletionPoliciesAnnotation.
+func parseKeyDeletionPolicies(value string) (KeyDeletionPolicies, error) {
+	var policies KeyDeletionPolicies
+	if err := json.Unmarshal([]byte(value), &policies); err != nil {
+		return KeyDeletionPolicies{}, fmt.Errorf("invalid %s annotation: %w", KeyDeletionPoliciesAnnotation, err)
+	}
+	for _, keyPolicies := range []map[string]DeletionPolicy{policies.Labels, policies.Annotations} {
+		for key, policy := range keyPolicies {
+			if policy != DeletionPolicyDelete && policy != DeletionPolicyRetain {
+				return KeyDeletionPolicies{}, fmt.Errorf("invalid %s annotation: unsupported deletion policy %q for key %s",
+					KeyDeletionPoliciesAnnotation, policy, key)
+			}
+		}
+	}
+	return policies, nil
+}
+
+// BestEffort reports whether the valid labels and annotations are applied even when others are rejected.
+func (s NamespaceLabelSpec) BestEffort() bool {
+	return s.ValidationMode == ValidationModeBestEffort
 }
 
 // NamespaceLabelStatus defines the observed state of NamespaceLabel
 type NamespaceLabelStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	LastSyncedTimeStamp *metav1.Time `json:"lastSyncedTimeStamp,omitempty"`
 
+	// AppliedLabels contains the labels of this NamespaceLabel that are currently applied to the namespace.
+	AppliedLabels map[string]string `json:"appliedLabels,omitempty"`
+
+	// RejectedLabels contains the labels of this NamespaceLabel that were not applied to the namespace.
+	RejectedLabels []RejectedLabel `json:"rejectedLabels,omitempty"`
+
+	// AppliedLabelCount is the number of AppliedLabels and AppliedAnnotations.
+	// +optional
+	AppliedLabelCount int `json:"appliedLabelCount,omitempty"`
+
+	// RejectedLabelCount is the number of RejectedLabels and RejectedAnnotations.
+	// +optional
+	RejectedLabelCount int `json:"rejectedLabelCount,omitempty"`
+
+	// AppliedAnnotations contains the annotations of this NamespaceLabel that are currently applied to the namespace.
+	AppliedAnnotations map[string]string `json:"appliedAnnotations,omitempty"`
+
+	// RejectedAnnotations contains the annotations of this NamespaceLabel that were not applied to the namespace.
+	RejectedAnnotations []RejectedLabel `json:"rejectedAnnotations,omitempty"`
+
+	// Plan lists the changes the NamespaceLabel would make to the namespace, it is only set for dry runs.
+	// +optional
+	Plan *NamespaceLabelPlan `json:"plan,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// NamespaceLabelPlan describes the changes a dry run would make to the labels and annotations of the namespace,
+// including the changes of the other label sources of the namespace.
+type NamespaceLabelPlan struct {
+	// +optional
+	Labels KeyChanges `json:"labels,omitempty"`
+
+	// +optional
+	Annotations KeyChanges `json:"annotations,omitempty"`
+}
+
+// KeyChanges lists the keys that would be added to, changed on or removed from the namespace.
+type KeyChanges struct {
+	// Added maps the keys that would be added to their value.
+	// +optional
+	Added map[string]string `json:"added,omitempty"`
+
+	// Changed maps the keys whose value would change to their new value.
+	// +optional
+	Changed map[string]string `json:"changed,omitempty"`
+
+	// Removed lists the keys that would be removed.
+	// +listType=set
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+}
+
+// RejectedLabel describes a label or annotation that was not applied to the namespace and why.
+type RejectedLabel struct {
+	Key string `json:"key"`
+
+	// Reason is a machine readable CamelCase reason for the rejection.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the rejection.
+	Message string `json:"message,omitempty"`
+}
+
+// Condition types reported on a NamespaceLabel.
+const (
+	// ConditionReady is True when all labels of the NamespaceLabel are applied to the namespace.
+	ConditionReady = "Ready"
+	// ConditionApplied is True when the accepted labels were successfully written to the namespace.
+	ConditionApplied = "Applied"
+	// ConditionConflicted is True when another NamespaceLabel sets a different value for one of the labels.
+	ConditionConflicted = "Conflicted"
+	// ConditionInvalid is True when the NamespaceLabel contains labels that cannot be applied.
+	ConditionInvalid = "Invalid"
+	// ConditionDefaultProtectedLabels is True when the protected labels ConfigMap does not exist and
+	// the built-in default protected labels are used instead.
+	ConditionDefaultProtectedLabels = "DefaultProtectedLabels"
+	// ConditionSuspended is True when the reconciliation of the NamespaceLabel is suspended.
+	ConditionSuspended = "Suspended"
+)
+
+// Reasons used for conditions and rejected labels.
+const (
+	ReasonLabelsApplied              = "LabelsApplied"
+	ReasonApplyFailed                = "ApplyFailed"
+	ReasonValidationFailed           = "ValidationFailed"
+	ReasonValid                      = "Valid"
+	ReasonProtectedLabel             = "ProtectedLabel"
+	ReasonInvalidKey                 = "InvalidKey"
+	ReasonInvalidValue               = "InvalidValue"
+	ReasonLabelConflict              = "LabelConflict"
+	ReasonNoConflicts                = "NoConflicts"
+	ReasonReconciled                 = "Reconciled"
+	ReasonProtectedLabelsUnavailable = "ProtectedLabelsUnavailable"
+	ReasonConfigMapNotFound          = "ConfigMapNotFound"
+	ReasonConfigMapFound             = "ConfigMapFound"
+	ReasonDryRun                     = "DryRun"
+	ReasonSuspended                  = "Suspended"
+	ReasonActive                     = "Active"
+	ReasonNamespaceTerminating       = "NamespaceTerminating"
+	ReasonNamespaceNotFound          = "NamespaceNotFound"
+)
+
+// Event reasons recorded for a NamespaceLabel and its Namespace.
+const (
+	// EventReasonLabelsApplied is recorded when labels are added to or changed on the namespace.
+	EventReasonLabelsApplied = "LabelsApplied"
+	// EventReasonLabelsRemoved is recorded when labels are removed from the namespace.
+	EventReasonLabelsRemoved = "LabelsRemoved"
+	// EventReasonLabelsRejected is recorded when a Strict NamespaceLabel is rejected for invalid labels.
+	EventReasonLabelsRejected = "LabelsRejected"
+	// EventReasonLabelsSkipped is recorded when a BestEffort NamespaceLabel skips invalid labels.
+	EventReasonLabelsSkipped = "LabelsSkipped"
+	// EventReasonLabelConflict is recorded when labels are in conflict with other label sources.
+	EventReasonLabelConflict = "LabelConflict"
+	// EventReasonDryRun is recorded when the planned changes of a dry run NamespaceLabel change.
+	EventReasonDryRun = "DryRun"
+	// EventReasonDriftCorrected is recorded when managed labels changed outside of the controller are restored.
+	EventReasonDriftCorrected = "DriftCorrected"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=nsl,categories=all-labels
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=".status.appliedLabelCount"
+// +kubebuilder:printcolumn:name="Rejected",type=integer,JSONPath=".status.rejectedLabelCount"
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=".status.lastSyncedTimeStamp"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 
 // NamespaceLabel is the Schema for the namespacelabels API
 type NamespaceLabel struct {
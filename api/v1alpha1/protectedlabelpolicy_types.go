@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PatternType is the syntax of a protected label pattern.
+// +kubebuilder:validation:Enum=Glob;Regex
+type PatternType string
+
+const (
+	// PatternTypeGlob matches label keys using '*' for any sequence of characters and '?' for a single character.
+	PatternTypeGlob PatternType = "Glob"
+	// PatternTypeRegex matches label keys using an RE2 regular expression anchored to the whole key.
+	PatternTypeRegex PatternType = "Regex"
+)
+
+// PolicyTarget is the kind of namespace metadata a ProtectedLabelPolicy protects.
+// +kubebuilder:validation:Enum=Labels;Annotations
+type PolicyTarget string
+
+const (
+	// PolicyTargetLabels protects namespace label keys.
+	PolicyTargetLabels PolicyTarget = "Labels"
+	// PolicyTargetAnnotations protects namespace annotation keys.
+	PolicyTargetAnnotations PolicyTarget = "Annotations"
+)
+
+// LabelPattern is a pattern matched against label keys.
+type LabelPattern struct {
+	// +kubebuilder:default=Glob
+	// +optional
+	Type PatternType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	Pattern string `json:"pattern"`
+}
+
+// ProtectedLabelPolicySpec defines the desired state of ProtectedLabelPolicy
+// +kubebuilder:validation:XValidation:rule="has(self.prefixes) || has(self.keys) || has(self.patterns)",message="at least one of prefixes, keys or patterns must be set"
+type ProtectedLabelPolicySpec struct {
+	// Description explains why the labels are protected.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Target is the kind of namespace metadata protected by the policy.
+	// +kubebuilder:default=Labels
+	// +optional
+	Target PolicyTarget `json:"target,omitempty"`
+
+	// Prefixes protects every label key starting with one of the prefixes.
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+
+	// Keys protects the exact label keys.
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Patterns protects every label key matching one of the patterns.
+	// +optional
+	Patterns []LabelPattern `json:"patterns,omitempty"`
+
+	// ExemptNamespaces lists the namespaces the policy does not apply to. Entries may be globs.
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+}
+
+// ProtectedLabelPolicyStatus defines the observed state of ProtectedLabelPolicy
+type ProtectedLabelPolicyStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Reasons used for ProtectedLabelPolicy conditions.
+const (
+	ReasonPolicyValid    = "PolicyValid"
+	ReasonInvalidPattern = "InvalidPattern"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// ProtectedLabelPolicy is the Schema for the protectedlabelpolicies API
+type ProtectedLabelPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProtectedLabelPolicySpec   `json:"spec,omitempty"`
+	Status ProtectedLabelPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProtectedLabelPolicyList contains a list of ProtectedLabelPolicy
+type ProtectedLabelPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProtectedLabelPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProtectedLabelPolicy{}, &ProtectedLabelPolicyList{})
+}
@@ -25,6 +25,204 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNamespaceLabel) DeepCopyInto(out *ClusterNamespaceLabel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNamespaceLabel.
+func (in *ClusterNamespaceLabel) DeepCopy() *ClusterNamespaceLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNamespaceLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterNamespaceLabel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNamespaceLabelList) DeepCopyInto(out *ClusterNamespaceLabelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterNamespaceLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNamespaceLabelList.
+func (in *ClusterNamespaceLabelList) DeepCopy() *ClusterNamespaceLabelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNamespaceLabelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterNamespaceLabelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNamespaceLabelSpec) DeepCopyInto(out *ClusterNamespaceLabelSpec) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNamespaceLabelSpec.
+func (in *ClusterNamespaceLabelSpec) DeepCopy() *ClusterNamespaceLabelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNamespaceLabelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNamespaceLabelStatus) DeepCopyInto(out *ClusterNamespaceLabelStatus) {
+	*out = *in
+	if in.LastSyncedTimeStamp != nil {
+		in, out := &in.LastSyncedTimeStamp, &out.LastSyncedTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.MatchedNamespaces != nil {
+		in, out := &in.MatchedNamespaces, &out.MatchedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNamespaceLabelStatus.
+func (in *ClusterNamespaceLabelStatus) DeepCopy() *ClusterNamespaceLabelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNamespaceLabelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyChanges) DeepCopyInto(out *KeyChanges) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyChanges.
+func (in *KeyChanges) DeepCopy() *KeyChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyDeletionPolicies) DeepCopyInto(out *KeyDeletionPolicies) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]DeletionPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]DeletionPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyDeletionPolicies.
+func (in *KeyDeletionPolicies) DeepCopy() *KeyDeletionPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyDeletionPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelPattern) DeepCopyInto(out *LabelPattern) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelPattern.
+func (in *LabelPattern) DeepCopy() *LabelPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceLabel) DeepCopyInto(out *NamespaceLabel) {
 	*out = *in
@@ -84,6 +282,23 @@ func (in *NamespaceLabelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceLabelPlan) DeepCopyInto(out *NamespaceLabelPlan) {
+	*out = *in
+	in.Labels.DeepCopyInto(&out.Labels)
+	in.Annotations.DeepCopyInto(&out.Annotations)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceLabelPlan.
+func (in *NamespaceLabelPlan) DeepCopy() *NamespaceLabelPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceLabelPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceLabelSpec) DeepCopyInto(out *NamespaceLabelSpec) {
 	*out = *in
@@ -94,6 +309,13 @@ func (in *NamespaceLabelSpec) DeepCopyInto(out *NamespaceLabelSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceLabelSpec.
@@ -113,6 +335,35 @@ func (in *NamespaceLabelStatus) DeepCopyInto(out *NamespaceLabelStatus) {
 		in, out := &in.LastSyncedTimeStamp, &out.LastSyncedTimeStamp
 		*out = (*in).DeepCopy()
 	}
+	if in.AppliedLabels != nil {
+		in, out := &in.AppliedLabels, &out.AppliedLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RejectedLabels != nil {
+		in, out := &in.RejectedLabels, &out.RejectedLabels
+		*out = make([]RejectedLabel, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedAnnotations != nil {
+		in, out := &in.AppliedAnnotations, &out.AppliedAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RejectedAnnotations != nil {
+		in, out := &in.RejectedAnnotations, &out.RejectedAnnotations
+		*out = make([]RejectedLabel, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(NamespaceLabelPlan)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -131,3 +382,159 @@ func (in *NamespaceLabelStatus) DeepCopy() *NamespaceLabelStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSelector) DeepCopyInto(out *NamespaceSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSelector.
+func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedLabelPolicy) DeepCopyInto(out *ProtectedLabelPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedLabelPolicy.
+func (in *ProtectedLabelPolicy) DeepCopy() *ProtectedLabelPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedLabelPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedLabelPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedLabelPolicyList) DeepCopyInto(out *ProtectedLabelPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProtectedLabelPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedLabelPolicyList.
+func (in *ProtectedLabelPolicyList) DeepCopy() *ProtectedLabelPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedLabelPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedLabelPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedLabelPolicySpec) DeepCopyInto(out *ProtectedLabelPolicySpec) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]LabelPattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptNamespaces != nil {
+		in, out := &in.ExemptNamespaces, &out.ExemptNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedLabelPolicySpec.
+func (in *ProtectedLabelPolicySpec) DeepCopy() *ProtectedLabelPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedLabelPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedLabelPolicyStatus) DeepCopyInto(out *ProtectedLabelPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedLabelPolicyStatus.
+func (in *ProtectedLabelPolicyStatus) DeepCopy() *ProtectedLabelPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedLabelPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RejectedLabel) DeepCopyInto(out *RejectedLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RejectedLabel.
+func (in *RejectedLabel) DeepCopy() *RejectedLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(RejectedLabel)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidationMode decides how a NamespaceLabel with invalid labels or annotations is applied.
+// +kubebuilder:validation:Enum=Strict;BestEffort
+type ValidationMode string
+
+const (
+	// ValidationModeStrict applies none of the changes to the NamespaceLabel while any of its labels or
+	// annotations is invalid, the labels and annotations applied last are kept on the namespace.
+	ValidationModeStrict ValidationMode = "Strict"
+	// ValidationModeBestEffort applies the valid labels and annotations and skips the invalid ones.
+	ValidationModeBestEffort ValidationMode = "BestEffort"
+)
+
+// DeletionPolicy decides what happens to the labels of a NamespaceLabel when it is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the labels and annotations from the namespace.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the labels and annotations on the namespace and stops managing them.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves every label and annotation on the namespace and stops managing them,
+	// ignoring the deletion policies set on individual keys.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// KeyDeletionPolicy decides what happens to a single label or annotation when its NamespaceLabel is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type KeyDeletionPolicy string
+
+const (
+	// KeyDeletionPolicyDelete removes the key from the namespace.
+	KeyDeletionPolicyDelete KeyDeletionPolicy = "Delete"
+	// KeyDeletionPolicyRetain leaves the key on the namespace and stops managing it.
+	KeyDeletionPolicyRetain KeyDeletionPolicy = "Retain"
+)
+
+// LabelEntry is a label or annotation applied to the namespace.
+type LabelEntry struct {
+	// Key is the label or annotation key.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the label or annotation value.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// DeletionPolicy overrides the DeletionPolicy of the NamespaceLabel for this key. It is ignored when
+	// the NamespaceLabel is orphaned.
+	// +optional
+	DeletionPolicy KeyDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// NamespaceLabelSpec defines the desired state of NamespaceLabel
+type NamespaceLabelSpec struct {
+	// Labels are applied to the namespace. System-reserved labels like 'kubernetes.io/' are not allowed.
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	Labels []LabelEntry `json:"labels,omitempty"`
+
+	// Annotations are applied to the namespace like labels. Protected annotations are configured with
+	// ProtectedLabelPolicies targeting annotations.
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	Annotations []LabelEntry `json:"annotations,omitempty"`
+
+	// Priority resolves conflicts with other NamespaceLabels in the same namespace when the controller
+	// runs with the Priority conflict strategy. The NamespaceLabel with the highest priority wins.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// ValidationMode decides whether the valid labels and annotations are applied while some of them
+	// are rejected. Rejected keys are reported in the status either way.
+	// +kubebuilder:default=Strict
+	// +optional
+	ValidationMode ValidationMode `json:"validationMode,omitempty"`
+
+	// DryRun computes the changes the NamespaceLabel would make to the namespace and reports them in
+	// status.plan and in events, without writing them to the namespace.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Suspend stops the reconciliation of the NamespaceLabel. The labels it applied last are kept on the
+	// namespace. Deleting a suspended NamespaceLabel still removes its labels.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DeletionPolicy decides whether the labels and annotations are removed from the namespace when the
+	// NamespaceLabel is deleted. Labels and annotations may override it with their own DeletionPolicy.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// NamespaceLabelStatus defines the observed state of NamespaceLabel
+type NamespaceLabelStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	LastSyncedTimeStamp *metav1.Time `json:"lastSyncedTimeStamp,omitempty"`
+
+	// AppliedLabels contains the labels of this NamespaceLabel that are currently applied to the namespace.
+	AppliedLabels map[string]string `json:"appliedLabels,omitempty"`
+
+	// RejectedLabels contains the labels of this NamespaceLabel that were not applied to the namespace.
+	RejectedLabels []RejectedLabel `json:"rejectedLabels,omitempty"`
+
+	// AppliedLabelCount is the number of AppliedLabels and AppliedAnnotations.
+	// +optional
+	AppliedLabelCount int `json:"appliedLabelCount,omitempty"`
+
+	// RejectedLabelCount is the number of RejectedLabels and RejectedAnnotations.
+	// +optional
+	RejectedLabelCount int `json:"rejectedLabelCount,omitempty"`
+
+	// AppliedAnnotations contains the annotations of this NamespaceLabel that are currently applied to the namespace.
+	AppliedAnnotations map[string]string `json:"appliedAnnotations,omitempty"`
+
+	// RejectedAnnotations contains the annotations of this NamespaceLabel that were not applied to the namespace.
+	RejectedAnnotations []RejectedLabel `json:"rejectedAnnotations,omitempty"`
+
+	// Plan lists the changes the NamespaceLabel would make to the namespace, it is only set for dry runs.
+	// +optional
+	Plan *NamespaceLabelPlan `json:"plan,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NamespaceLabelPlan describes the changes a dry run would make to the labels and annotations of the namespace,
+// including the changes of the other label sources of the namespace.
+type NamespaceLabelPlan struct {
+	// +optional
+	Labels KeyChanges `json:"labels,omitempty"`
+
+	// +optional
+	Annotations KeyChanges `json:"annotations,omitempty"`
+}
+
+// KeyChanges lists the keys that would be added to, changed on or removed from the namespace.
+type KeyChanges struct {
+	// Added maps the keys that would be added to their value.
+	// +optional
+	Added map[string]string `json:"added,omitempty"`
+
+	// Changed maps the keys whose value would change to their new value.
+	// +optional
+	Changed map[string]string `json:"changed,omitempty"`
+
+	// Removed lists the keys that would be removed.
+	// +listType=set
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+}
+
+// RejectedLabel describes a label or annotation that was not applied to the namespace and why.
+type RejectedLabel struct {
+	Key string `json:"key"`
+
+	// Reason is a machine readable CamelCase reason for the rejection.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the rejection.
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=nsl,categories=all-labels
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=".status.appliedLabelCount"
+// +kubebuilder:printcolumn:name="Rejected",type=integer,JSONPath=".status.rejectedLabelCount"
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=".status.lastSyncedTimeStamp"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// NamespaceLabel is the Schema for the namespacelabels API
+type NamespaceLabel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceLabelSpec   `json:"spec,omitempty"`
+	Status NamespaceLabelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceLabelList contains a list of NamespaceLabel
+type NamespaceLabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceLabel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceLabel{}, &NamespaceLabelList{})
+}
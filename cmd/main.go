@@ -19,16 +19,22 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"slices"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,7 +42,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	namespacelabelv1beta1 "github.com/oshribelay/namespace-label/api/v1beta1"
 	"github.com/oshribelay/namespace-label/internal/controller"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+	webhooknamespacelabelv1alpha1 "github.com/oshribelay/namespace-label/internal/webhook/v1alpha1"
+	webhooknamespacelabelv1beta1 "github.com/oshribelay/namespace-label/internal/webhook/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +60,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(namespacelabelv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(namespacelabelv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -58,6 +70,12 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var conflictStrategy string
+	var protectedLabelAction string
+	var requireProtectedLabelsConfigMap bool
+	var protectedLabelsConfigMapName string
+	var protectedLabelsConfigMapNamespace string
+	var dryRun bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +87,24 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&conflictStrategy, "conflict-strategy", string(resources.ConflictStrategyFirstCreated),
+		fmt.Sprintf("How conflicting values set by several NamespaceLabels in a namespace are resolved. One of %v.",
+			resources.ConflictStrategies))
+	flag.StringVar(&protectedLabelAction, "protected-label-action", string(resources.ProtectedLabelActionRetain),
+		fmt.Sprintf("What happens to managed namespace labels once their key becomes protected. One of %v.",
+			resources.ProtectedLabelActions))
+	flag.BoolVar(&requireProtectedLabelsConfigMap, "require-protected-labels-configmap", false,
+		"If set, NamespaceLabels are not reconciled while the protected labels ConfigMap is missing "+
+			"instead of falling back to the default protected labels.")
+	flag.StringVar(&protectedLabelsConfigMapName, "protected-labels-configmap-name",
+		envOrDefault("PROTECTED_LABELS_CONFIGMAP_NAME", policy.ProtectedLabelsConfigMapName),
+		"The name of the ConfigMap holding the protected labels.")
+	flag.StringVar(&protectedLabelsConfigMapNamespace, "protected-labels-configmap-namespace",
+		os.Getenv("PROTECTED_LABELS_CONFIGMAP_NAMESPACE"),
+		"The namespace of the ConfigMap holding the protected labels. Defaults to the namespace of the manager.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, the changes of every NamespaceLabel and ClusterNamespaceLabel are planned and reported in their "+
+			"status and events without writing to namespaces.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -77,6 +113,21 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if !slices.Contains(resources.ConflictStrategies, resources.ConflictStrategy(conflictStrategy)) {
+		setupLog.Error(fmt.Errorf("unknown conflict strategy %q", conflictStrategy), "invalid flags")
+		os.Exit(1)
+	}
+	if !slices.Contains(resources.ProtectedLabelActions, resources.ProtectedLabelAction(protectedLabelAction)) {
+		setupLog.Error(fmt.Errorf("unknown protected label action %q", protectedLabelAction), "invalid flags")
+		os.Exit(1)
+	}
+
+	if protectedLabelsConfigMapNamespace == "" {
+		protectedLabelsConfigMapNamespace = envOrDefault("POD_NAMESPACE", policy.ProtectedLabelsConfigMapNamespace)
+	}
+	setupLog.Info("using protected labels ConfigMap",
+		"name", protectedLabelsConfigMapName, "namespace", protectedLabelsConfigMapNamespace)
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -120,8 +171,20 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	protectedLabelsSource := policy.Source{
+		RequireConfigMap: requireProtectedLabelsConfigMap,
+		ConfigMap: types.NamespacedName{
+			Name:      protectedLabelsConfigMapName,
+			Namespace: protectedLabelsConfigMapNamespace,
+		},
+	}
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
+		Scheme: scheme,
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}: protectedLabelsSource.ConfigMapCache(),
+			},
+		},
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -145,12 +208,52 @@ func main() {
 	}
 
 	if err = (&controller.NamespaceLabelReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		ConflictStrategy:      resources.ConflictStrategy(conflictStrategy),
+		ProtectedLabelAction:  resources.ProtectedLabelAction(protectedLabelAction),
+		ProtectedLabelsSource: protectedLabelsSource,
+		Recorder:              mgr.GetEventRecorderFor("namespacelabel-controller"),
+		DryRun:                dryRun,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceLabel")
+		os.Exit(1)
+	}
+	if err = (&controller.ProtectedLabelPolicyReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "NamespaceLabel")
+		setupLog.Error(err, "unable to create controller", "controller", "ProtectedLabelPolicy")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterNamespaceLabelReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		ConflictStrategy:      resources.ConflictStrategy(conflictStrategy),
+		ProtectedLabelAction:  resources.ProtectedLabelAction(protectedLabelAction),
+		ProtectedLabelsSource: protectedLabelsSource,
+		Recorder:              mgr.GetEventRecorderFor("clusternamespacelabel-controller"),
+		DryRun:                dryRun,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterNamespaceLabel")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknamespacelabelv1alpha1.SetupNamespaceLabelWebhookWithManager(mgr,
+			resources.ConflictStrategy(conflictStrategy), protectedLabelsSource); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespaceLabel")
+			os.Exit(1)
+		}
+		if err = webhooknamespacelabelv1alpha1.SetupProtectedLabelPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ProtectedLabelPolicy")
+			os.Exit(1)
+		}
+		if err = webhooknamespacelabelv1beta1.SetupNamespaceLabelWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespaceLabel")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -168,3 +271,11 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// envOrDefault returns the value of the environment variable key, or fallback when it is unset or empty.
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/finalizer"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+)
+
+// ClusterNamespaceLabelReconciler reconciles a ClusterNamespaceLabel object
+type ClusterNamespaceLabelReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// ConflictStrategy decides which label source wins when several set the same label to different values.
+	ConflictStrategy resources.ConflictStrategy
+
+	// ProtectedLabelAction decides whether managed labels that become protected are removed from the namespace.
+	ProtectedLabelAction resources.ProtectedLabelAction
+
+	// ProtectedLabelsSource configures how the protected labels are loaded.
+	ProtectedLabelsSource policy.Source
+
+	// Recorder records events on the namespaces whose labels change.
+	Recorder record.EventRecorder
+
+	// DryRun plans the changes of every ClusterNamespaceLabel without writing to namespaces.
+	DryRun bool
+}
+
+// clusterLabelSync is the outcome of reconciling the labels of every namespace selected by a ClusterNamespaceLabel.
+type clusterLabelSync struct {
+	// matched are the sorted names of the namespaces selected by the ClusterNamespaceLabel.
+	matched []string
+	// rejected are the labels and annotations, sorted by key, that are invalid or protected in at least one
+	// selected namespace. Annotation keys are prefixed with "annotation ".
+	rejected []namespacelabelv1alpha1.RejectedLabel
+	// conflicted are the sorted names of the namespaces where another label source won a label.
+	conflicted []string
+	// failed describes the namespaces whose labels could not be written.
+	failed []string
+	// err is set when the selected namespaces could not be determined.
+	err error
+	// failureReason is the condition reason reported together with err.
+	failureReason string
+	// protectedLabels are the protected labels used for the sync, nil when they could not be loaded.
+	protectedLabels *policy.ProtectedLabels
+}
+
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=clusternamespacelabels,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=clusternamespacelabels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=clusternamespacelabels/finalizers,verbs=update
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=namespacelabels,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile applies the labels of the ClusterNamespaceLabel to every namespace it selects and
+// releases them from the namespaces it no longer selects.
+func (r *ClusterNamespaceLabelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling ClusterNamespaceLabel", "ClusterNamespaceLabel", req.Name)
+
+	clusterNsLabel := namespacelabelv1alpha1.ClusterNamespaceLabel{}
+	if err := r.Get(ctx, req.NamespacedName, &clusterNsLabel); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to fetch ClusterNamespaceLabel")
+		return ctrl.Result{}, err
+	}
+
+	protectedLabels, err := r.ProtectedLabelsSource.Load(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to load protected labels")
+		if statusErr := r.updateStatus(ctx, &clusterNsLabel, clusterLabelSync{
+			matched:       clusterNsLabel.Status.MatchedNamespaces,
+			err:           err,
+			failureReason: namespacelabelv1alpha1.ReasonProtectedLabelsUnavailable,
+		}); statusErr != nil {
+			logger.Error(statusErr, "Failed to update ClusterNamespaceLabel status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	namespaceList := corev1.NamespaceList{}
+	if err := r.List(ctx, &namespaceList); err != nil {
+		logger.Error(err, "Failed to fetch Namespaces")
+		return ctrl.Result{}, err
+	}
+
+	if !clusterNsLabel.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.handleDeletion(ctx, clusterNsLabel, namespaceList, protectedLabels)
+	}
+
+	if err := resources.ValidateNamespaceSelector(clusterNsLabel.Spec.NamespaceSelector); err != nil {
+		// the selected namespaces are unknown, leave the labels of the previously selected ones untouched
+		if statusErr := r.updateStatus(ctx, &clusterNsLabel, clusterLabelSync{
+			matched:         clusterNsLabel.Status.MatchedNamespaces,
+			err:             err,
+			failureReason:   namespacelabelv1alpha1.ReasonInvalidSelector,
+			protectedLabels: protectedLabels,
+		}); statusErr != nil {
+			logger.Error(statusErr, "Failed to update ClusterNamespaceLabel status")
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := finalizer.EnsureFinalizer(ctx, r.Client, &clusterNsLabel); err != nil {
+		logger.Error(err, "unable to add finalizer")
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	sync := clusterLabelSync{protectedLabels: protectedLabels}
+	rejected := make(map[string]namespacelabelv1alpha1.RejectedLabel)
+	previouslyMatched := make(map[string]bool, len(clusterNsLabel.Status.MatchedNamespaces))
+	for _, name := range clusterNsLabel.Status.MatchedNamespaces {
+		previouslyMatched[name] = true
+	}
+	for _, namespace := range namespaceList.Items {
+		matches, _ := resources.MatchesNamespace(clusterNsLabel.Spec.NamespaceSelector, namespace)
+		if !matches && !previouslyMatched[namespace.Name] {
+			continue
+		}
+		merge, err := r.namespaceSyncer().updateNamespaceLabels(ctx, namespace, protectedLabels)
+		if err != nil {
+			logger.Error(err, "Failed to sync namespace labels", "namespace", namespace.Name)
+			sync.failed = append(sync.failed, fmt.Sprintf("%s (%v)", namespace.Name, err))
+		}
+		if !matches {
+			continue
+		}
+		sync.matched = append(sync.matched, namespace.Name)
+		if err != nil {
+			continue
+		}
+		namespaceRejected := make(map[string]bool)
+		for _, label := range resources.RejectedLabels(namespace.Name, clusterNsLabel.Spec.Labels, protectedLabels) {
+			namespaceRejected[label.Key] = true
+			rejected[label.Key] = label
+		}
+		for _, annotation := range resources.RejectedAnnotations(namespace.Name, clusterNsLabel.Spec.Annotations, protectedLabels) {
+			annotation.Key = "annotation " + annotation.Key
+			namespaceRejected[annotation.Key] = true
+			rejected[annotation.Key] = annotation
+		}
+		conflicted := false
+		for key, value := range clusterNsLabel.Spec.Labels {
+			if !namespaceRejected[key] && merge.labels.Values[key] != value {
+				conflicted = true
+			}
+		}
+		for key, value := range clusterNsLabel.Spec.Annotations {
+			if !namespaceRejected["annotation "+key] && merge.annotations.Values[key] != value {
+				conflicted = true
+			}
+		}
+		if conflicted {
+			sync.conflicted = append(sync.conflicted, namespace.Name)
+		}
+	}
+	for _, label := range rejected {
+		sync.rejected = append(sync.rejected, label)
+	}
+	sort.Strings(sync.matched)
+	sort.Slice(sync.rejected, func(i, j int) bool {
+		return sync.rejected[i].Key < sync.rejected[j].Key
+	})
+	sort.Strings(sync.conflicted)
+	sort.Strings(sync.failed)
+
+	if err := r.updateStatus(ctx, &clusterNsLabel, sync); err != nil {
+		logger.Error(err, "Failed to update ClusterNamespaceLabel status")
+		return ctrl.Result{}, err
+	}
+	if len(sync.failed) > 0 {
+		return ctrl.Result{}, fmt.Errorf("failed to apply labels to namespaces: %s", strings.Join(sync.failed, ", "))
+	}
+	return ctrl.Result{}, nil
+}
+
+// handleDeletion releases the labels of the ClusterNamespaceLabel from every namespace it selected
+// before removing its finalizer. The ClusterNamespaceLabel being deleted is no longer a label source
+// of any namespace, so its labels are released by the sync.
+func (r *ClusterNamespaceLabelReconciler) handleDeletion(ctx context.Context, clusterNsLabel namespacelabelv1alpha1.ClusterNamespaceLabel, namespaceList corev1.NamespaceList, protectedLabels *policy.ProtectedLabels) error {
+	logger := log.FromContext(ctx)
+
+	previouslyMatched := make(map[string]bool, len(clusterNsLabel.Status.MatchedNamespaces))
+	for _, name := range clusterNsLabel.Status.MatchedNamespaces {
+		previouslyMatched[name] = true
+	}
+	for _, namespace := range namespaceList.Items {
+		matches, _ := resources.MatchesNamespace(clusterNsLabel.Spec.NamespaceSelector, namespace)
+		if !matches && !previouslyMatched[namespace.Name] {
+			continue
+		}
+		if _, err := r.namespaceSyncer().updateNamespaceLabels(ctx, namespace, protectedLabels); err != nil {
+			logger.Error(err, "Failed to remove deleted labels from the namespace", "namespace", namespace.Name)
+			return err
+		}
+	}
+
+	if err := finalizer.RemoveFinalizer(ctx, r.Client, &clusterNsLabel); err != nil {
+		logger.Error(err, "Failed to remove finalizer")
+		return err
+	}
+	return nil
+}
+
+// updateStatus records the selected namespaces and conditions of the ClusterNamespaceLabel and
+// writes them through the status subresource.
+func (r *ClusterNamespaceLabelReconciler) updateStatus(ctx context.Context, clusterNsLabel *namespacelabelv1alpha1.ClusterNamespaceLabel, sync clusterLabelSync) error {
+	status := &clusterNsLabel.Status
+	now := metav1.Now()
+	status.ObservedGeneration = clusterNsLabel.Generation
+	status.LastSyncedTimeStamp = &now
+	status.MatchedNamespaces = sync.matched
+
+	setClusterCondition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: clusterNsLabel.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	if sync.err != nil {
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, sync.failureReason, sync.err.Error())
+		return r.Status().Update(ctx, clusterNsLabel)
+	}
+
+	if len(sync.rejected) > 0 {
+		setClusterCondition(namespacelabelv1alpha1.ConditionInvalid, metav1.ConditionTrue, sync.rejected[0].Reason,
+			"labels rejected: "+rejectedKeys(sync.rejected))
+	} else {
+		setClusterCondition(namespacelabelv1alpha1.ConditionInvalid, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonValid,
+			"all labels are valid")
+	}
+
+	if len(sync.conflicted) > 0 {
+		setClusterCondition(namespacelabelv1alpha1.ConditionConflicted, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonLabelConflict,
+			"labels in conflict with other label sources in namespaces: "+strings.Join(sync.conflicted, ", "))
+	} else {
+		setClusterCondition(namespacelabelv1alpha1.ConditionConflicted, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonNoConflicts,
+			"no label conflicts with other label sources")
+	}
+
+	if sync.protectedLabels != nil {
+		configMap := r.ProtectedLabelsSource.ConfigMapKey()
+		if sync.protectedLabels.UsesDefaults() {
+			setClusterCondition(namespacelabelv1alpha1.ConditionDefaultProtectedLabels, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonConfigMapNotFound,
+				fmt.Sprintf("ConfigMap %s not found, using the default protected labels %s",
+					configMap, policy.DefaultProtectedLabelsDescription()))
+		} else {
+			setClusterCondition(namespacelabelv1alpha1.ConditionDefaultProtectedLabels, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonConfigMapFound,
+				fmt.Sprintf("protected labels are read from ConfigMap %s", configMap))
+		}
+	}
+
+	if len(sync.failed) > 0 {
+		setClusterCondition(namespacelabelv1alpha1.ConditionApplied, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonApplyFailed,
+			"failed to apply labels to namespaces: "+strings.Join(sync.failed, ", "))
+	} else if r.DryRun {
+		setClusterCondition(namespacelabelv1alpha1.ConditionApplied, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonDryRun,
+			fmt.Sprintf("dry run, labels are not written to the %d selected namespaces", len(sync.matched)))
+	} else {
+		setClusterCondition(namespacelabelv1alpha1.ConditionApplied, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonLabelsApplied,
+			fmt.Sprintf("labels applied to %d namespaces", len(sync.matched)))
+	}
+
+	switch {
+	case len(sync.failed) > 0:
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonApplyFailed,
+			"labels could not be applied to some namespaces")
+	case len(sync.rejected) > 0:
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, sync.rejected[0].Reason,
+			"some labels were rejected")
+	case len(sync.conflicted) > 0:
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonLabelConflict,
+			"some labels are owned by another label source")
+	case r.DryRun:
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonDryRun,
+			"the controller runs in dry run mode")
+	default:
+		setClusterCondition(namespacelabelv1alpha1.ConditionReady, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonReconciled,
+			"all labels are applied to the selected namespaces")
+	}
+
+	return r.Status().Update(ctx, clusterNsLabel)
+}
+
+// namespaceSyncer returns the namespaceSyncer configured for the reconciler.
+func (r *ClusterNamespaceLabelReconciler) namespaceSyncer() namespaceSyncer {
+	return namespaceSyncer{
+		Client:               r.Client,
+		conflictStrategy:     r.ConflictStrategy,
+		protectedLabelAction: r.ProtectedLabelAction,
+		recorder:             r.Recorder,
+		dryRun:               r.DryRun,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterNamespaceLabelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&namespacelabelv1alpha1.ClusterNamespaceLabel{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterNamespaceLabelsOfNamespace),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
+		).
+		Watches(&namespacelabelv1alpha1.NamespaceLabel{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterNamespaceLabelsOfNamespaceLabel),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.allClusterNamespaceLabels),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.isProtectedLabelsConfigMap)),
+		).
+		Watches(&namespacelabelv1alpha1.ProtectedLabelPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.allClusterNamespaceLabels),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// isProtectedLabelsConfigMap reports whether the object is the protected labels ConfigMap.
+func (r *ClusterNamespaceLabelReconciler) isProtectedLabelsConfigMap(obj client.Object) bool {
+	return client.ObjectKeyFromObject(obj) == r.ProtectedLabelsSource.ConfigMapKey()
+}
+
+// allClusterNamespaceLabels returns requests for every ClusterNamespaceLabel, so that changes to the
+// protected labels are applied everywhere.
+func (r *ClusterNamespaceLabelReconciler) allClusterNamespaceLabels(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.requestsFor(ctx, func(namespacelabelv1alpha1.ClusterNamespaceLabel) bool { return true })
+}
+
+// clusterNamespaceLabelsOfNamespace maps a Namespace to requests for every ClusterNamespaceLabel that
+// selects it or selected it before, so that created and relabeled namespaces are re-evaluated.
+func (r *ClusterNamespaceLabelReconciler) clusterNamespaceLabelsOfNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return nil
+	}
+	return r.requestsFor(ctx, func(clusterNsLabel namespacelabelv1alpha1.ClusterNamespaceLabel) bool {
+		matches, _ := resources.MatchesNamespace(clusterNsLabel.Spec.NamespaceSelector, *namespace)
+		return matches || slices.Contains(clusterNsLabel.Status.MatchedNamespaces, namespace.Name)
+	})
+}
+
+// clusterNamespaceLabelsOfNamespaceLabel maps a NamespaceLabel to requests for every ClusterNamespaceLabel
+// selecting its namespace, so that their status follows conflicts introduced or resolved by the NamespaceLabel.
+func (r *ClusterNamespaceLabelReconciler) clusterNamespaceLabelsOfNamespaceLabel(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsFor(ctx, func(clusterNsLabel namespacelabelv1alpha1.ClusterNamespaceLabel) bool {
+		return slices.Contains(clusterNsLabel.Status.MatchedNamespaces, obj.GetNamespace())
+	})
+}
+
+// requestsFor returns requests for every ClusterNamespaceLabel accepted by the filter.
+func (r *ClusterNamespaceLabelReconciler) requestsFor(ctx context.Context, filter func(namespacelabelv1alpha1.ClusterNamespaceLabel) bool) []reconcile.Request {
+	clusterNamespaceLabelList := namespacelabelv1alpha1.ClusterNamespaceLabelList{}
+	if err := r.List(ctx, &clusterNamespaceLabelList); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list ClusterNamespaceLabels")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, clusterNsLabel := range clusterNamespaceLabelList.Items {
+		if filter(clusterNsLabel) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterNsLabel.Name}})
+		}
+	}
+	return requests
+}
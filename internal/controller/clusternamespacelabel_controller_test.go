@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/utils"
+)
+
+var _ = Describe("ClusterNamespaceLabel Controller", func() {
+	Context("When reconciling a ClusterNamespaceLabel object", func() {
+
+		const (
+			timeout  = time.Second * 10
+			interval = time.Second * 1
+		)
+
+		ctx := context.Background()
+
+		var (
+			namespace      *corev1.Namespace
+			clusterNsLabel *namespacelabelv1alpha1.ClusterNamespaceLabel
+		)
+
+		BeforeEach(func() {
+			By("creating a namespace selected by the ClusterNamespaceLabel")
+			namespace = &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster-label-" + utils.GenerateRandomString(10),
+					Labels: map[string]string{"environment": "test"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+			By("creating the ClusterNamespaceLabel")
+			clusterNsLabel = &namespacelabelv1alpha1.ClusterNamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusternamespacelabel-" + utils.GenerateRandomString(10)},
+				Spec: namespacelabelv1alpha1.ClusterNamespaceLabelSpec{
+					NamespaceSelector: namespacelabelv1alpha1.NamespaceSelector{
+						LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "test"}},
+						Names:         []string{"cluster-label-*"},
+					},
+					Labels: map[string]string{"cost-center": "platform"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, clusterNsLabel)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("deleting the ClusterNamespaceLabel")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, clusterNsLabel))).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterNsLabel), clusterNsLabel))
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should apply the labels to the selected namespaces", func() {
+			By("verifying the label was applied to the namespace")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("cost-center", "platform"))
+
+			By("verifying the status lists the selected namespace")
+			Eventually(func() []string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterNsLabel), clusterNsLabel); err != nil {
+					return nil
+				}
+				return clusterNsLabel.Status.MatchedNamespaces
+			}, timeout, interval).Should(ContainElement(namespace.Name))
+			Expect(meta.IsStatusConditionTrue(clusterNsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionReady)).To(BeTrue())
+		})
+
+		It("should reject a ClusterNamespaceLabel with an empty namespace selector", func() {
+			empty := &namespacelabelv1alpha1.ClusterNamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusternamespacelabel-" + utils.GenerateRandomString(10)},
+				Spec: namespacelabelv1alpha1.ClusterNamespaceLabelSpec{
+					Labels: map[string]string{"cost-center": "everyone"},
+				},
+			}
+			err := k8sClient.Create(ctx, empty)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("at least one of labelSelector or names must be set"))
+		})
+
+		It("should release the labels of namespaces that are no longer selected", func() {
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("cost-center", "platform"))
+
+			By("relabeling the namespace so it is no longer selected")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return err
+				}
+				namespace.Labels["environment"] = "production"
+				return k8sClient.Update(ctx, namespace)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the label was removed from the namespace")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).ShouldNot(HaveKey("cost-center"))
+			Expect(namespace.Labels).To(HaveKeyWithValue("environment", "production"))
+		})
+
+		It("should release the labels when the ClusterNamespaceLabel is deleted", func() {
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("cost-center", "platform"))
+
+			By("deleting the ClusterNamespaceLabel")
+			Expect(k8sClient.Delete(ctx, clusterNsLabel)).To(Succeed())
+
+			By("verifying the label was removed from the namespace")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).ShouldNot(HaveKey("cost-center"))
+		})
+	})
+})
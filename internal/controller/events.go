@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+// maxEventKeys is the number of keys listed in a single event, so that events do not grow with the number
+// of labels changed at once.
+const maxEventKeys = 10
+
+// keyChanges returns the sorted keys whose value differs in desired from previous and the sorted keys of
+// previous missing from desired.
+func keyChanges(previous, desired map[string]string) (changed, removed []string) {
+	for key, value := range desired {
+		if current, exists := previous[key]; !exists || current != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, exists := desired[key]; !exists {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed
+}
+
+// describeKeys lists the labels and annotations in a single message, such as "labels a, b; annotations c".
+// At most maxEventKeys keys are listed per kind.
+func describeKeys(labels, annotations []string) string {
+	var parts []string
+	for _, kind := range []struct {
+		name string
+		keys []string
+	}{{"labels", labels}, {"annotations", annotations}} {
+		if len(kind.keys) == 0 {
+			continue
+		}
+		keys := kind.keys
+		if len(keys) > maxEventKeys {
+			keys = append(keys[:maxEventKeys:maxEventKeys], fmt.Sprintf("and %d more", len(kind.keys)-maxEventKeys))
+		}
+		parts = append(parts, kind.name+" "+strings.Join(keys, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// recordChanges records a single event of the given type and reason describing the changed labels and
+// annotations, nothing is recorded without changes or without a recorder.
+func recordChanges(recorder record.EventRecorder, object runtime.Object, eventType, reason, action string, labels, annotations []string) {
+	if recorder == nil || len(labels)+len(annotations) == 0 {
+		return
+	}
+	recorder.Event(object, eventType, reason, action+" "+describeKeys(labels, annotations))
+}
+
+// recordStatusEvents records events for the changes between the previous and the current status of the
+// NamespaceLabel. Events are only recorded when the applied keys, rejections or conflicts change, so that
+// periodic reconciles of an unchanged NamespaceLabel do not produce event storms. Rejections and conflicts
+// are recorded on the namespace too, when it is given; the applied and removed keys are recorded on the
+// namespace by the namespaceSyncer.
+func (r *NamespaceLabelReconciler) recordStatusEvents(nsLabel *namespacelabelv1alpha1.NamespaceLabel, previous namespacelabelv1alpha1.NamespaceLabelStatus, namespace *corev1.Namespace) {
+	if r.Recorder == nil {
+		return
+	}
+	status := nsLabel.Status
+
+	changedLabels, removedLabels := keyChanges(previous.AppliedLabels, status.AppliedLabels)
+	changedAnnotations, removedAnnotations := keyChanges(previous.AppliedAnnotations, status.AppliedAnnotations)
+	recordChanges(r.Recorder, nsLabel, corev1.EventTypeNormal, namespacelabelv1alpha1.EventReasonLabelsApplied,
+		"applied", changedLabels, changedAnnotations)
+	recordChanges(r.Recorder, nsLabel, corev1.EventTypeNormal, namespacelabelv1alpha1.EventReasonLabelsRemoved,
+		"removed", removedLabels, removedAnnotations)
+
+	if invalid := conditionChanged(previous, status, namespacelabelv1alpha1.ConditionInvalid); invalid != nil {
+		reason := namespacelabelv1alpha1.EventReasonLabelsRejected
+		if nsLabel.Spec.BestEffort() {
+			reason = namespacelabelv1alpha1.EventReasonLabelsSkipped
+		}
+		recordOnBoth(r.Recorder, nsLabel, namespace, corev1.EventTypeWarning, reason, invalid.Message)
+	}
+	if status.Plan != nil && !equality.Semantic.DeepEqual(previous.Plan, status.Plan) {
+		r.Recorder.Event(nsLabel, corev1.EventTypeNormal, namespacelabelv1alpha1.EventReasonDryRun,
+			"dry run, planned changes: "+describePlan(status.Plan))
+	}
+	if conflicted := conditionChanged(previous, status, namespacelabelv1alpha1.ConditionConflicted); conflicted != nil {
+		recordOnBoth(r.Recorder, nsLabel, namespace, corev1.EventTypeWarning, namespacelabelv1alpha1.EventReasonLabelConflict, conflicted.Message)
+	}
+}
+
+// recordOnBoth records the event on the NamespaceLabel and, naming the NamespaceLabel, on its namespace
+// when it is not nil.
+func recordOnBoth(recorder record.EventRecorder, nsLabel *namespacelabelv1alpha1.NamespaceLabel, namespace *corev1.Namespace, eventType, reason, message string) {
+	recorder.Event(nsLabel, eventType, reason, message)
+	if namespace != nil {
+		recorder.Eventf(namespace, eventType, reason, "NamespaceLabel %s: %s", nsLabel.Name, message)
+	}
+}
+
+// conditionChanged returns the condition of the given type when it is True in the current status and was
+// not True with the same message for the same generation in the previous status.
+func conditionChanged(previous, current namespacelabelv1alpha1.NamespaceLabelStatus, conditionType string) *metav1.Condition {
+	condition := meta.FindStatusCondition(current.Conditions, conditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return nil
+	}
+	if before := meta.FindStatusCondition(previous.Conditions, conditionType); before != nil &&
+		before.Status == condition.Status && before.Message == condition.Message && before.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	return condition
+}
@@ -3,31 +3,45 @@ package finalizer
 import (
 	"context"
 
-	"github.com/oshribelay/namespace-label/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const namespaceLabelFinalizer = "finalizer.namespacelabel.dana.io"
 
-// EnsureFinalizer adds a finalizer to the NamespaceLabel object.
-func EnsureFinalizer(ctx context.Context, c client.Client, namespaceLabel *v1alpha1.NamespaceLabel) error {
-	if !controllerutil.ContainsFinalizer(namespaceLabel, namespaceLabelFinalizer) {
-		controllerutil.AddFinalizer(namespaceLabel, namespaceLabelFinalizer)
-		if err := c.Update(ctx, namespaceLabel); err != nil {
-			return err
-		}
-	}
-	return nil
+// EnsureFinalizer adds the finalizer to obj, which may be of any kind managed by the controller.
+func EnsureFinalizer(ctx context.Context, c client.Client, obj client.Object) error {
+	return patchFinalizers(ctx, c, obj, func() bool {
+		return controllerutil.AddFinalizer(obj, namespaceLabelFinalizer)
+	})
+}
+
+// RemoveFinalizer removes the finalizer from obj, which may be of any kind managed by the controller.
+func RemoveFinalizer(ctx context.Context, c client.Client, obj client.Object) error {
+	return patchFinalizers(ctx, c, obj, func() bool {
+		return controllerutil.RemoveFinalizer(obj, namespaceLabelFinalizer)
+	})
 }
 
-// RemoveFinalizer removes a finalizer from the NamespaceLabel object.
-func RemoveFinalizer(ctx context.Context, c client.Client, namespaceLabel *v1alpha1.NamespaceLabel) error {
-	if controllerutil.ContainsFinalizer(namespaceLabel, namespaceLabelFinalizer) {
-		controllerutil.RemoveFinalizer(namespaceLabel, namespaceLabelFinalizer)
-		if err := c.Update(ctx, namespaceLabel); err != nil {
-			return err
+// patchFinalizers applies mutate to the finalizers of obj and writes them with a merge patch guarded by
+// the resource version of obj, so a stale copy never overwrites concurrent changes. On conflicts obj is
+// read again and the patch is retried.
+func patchFinalizers(ctx context.Context, c client.Client, obj client.Object, mutate func() bool) error {
+	refresh := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if refresh {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		original := obj.DeepCopyObject().(client.Object)
+		if !mutate() {
+			return nil
 		}
-	}
-	return nil
+		err := c.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+		refresh = apierrors.IsConflict(err)
+		return err
+	})
 }
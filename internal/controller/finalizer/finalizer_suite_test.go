@@ -0,0 +1,14 @@
+package finalizer
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFinalizer(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Finalizer Suite")
+}
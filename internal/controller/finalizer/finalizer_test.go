@@ -0,0 +1,95 @@
+package finalizer
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+var _ = Describe("Finalizer", func() {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	var nsLabel *v1alpha1.NamespaceLabel
+
+	BeforeEach(func() {
+		nsLabel = &v1alpha1.NamespaceLabel{
+			ObjectMeta: metav1.ObjectMeta{Name: "labels", Namespace: "default"},
+			Spec:       v1alpha1.NamespaceLabelSpec{Labels: map[string]string{"team": "a"}},
+		}
+	})
+
+	It("should add and remove the finalizer", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nsLabel).Build()
+
+		Expect(EnsureFinalizer(ctx, c, nsLabel)).To(Succeed())
+		Expect(EnsureFinalizer(ctx, c, nsLabel)).To(Succeed())
+		stored := &v1alpha1.NamespaceLabel{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nsLabel), stored)).To(Succeed())
+		Expect(stored.Finalizers).To(ConsistOf(namespaceLabelFinalizer))
+
+		Expect(RemoveFinalizer(ctx, c, nsLabel)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nsLabel), stored)).To(Succeed())
+		Expect(stored.Finalizers).To(BeEmpty())
+	})
+
+	It("should not overwrite concurrent changes from a stale copy", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nsLabel).Build()
+		stale := &v1alpha1.NamespaceLabel{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nsLabel), stale)).To(Succeed())
+
+		By("changing the spec and finalizers concurrently")
+		current := stale.DeepCopy()
+		current.Spec.Labels = map[string]string{"team": "b"}
+		current.Finalizers = []string{"example.com/other"}
+		Expect(c.Update(ctx, current)).To(Succeed())
+
+		Expect(EnsureFinalizer(ctx, c, stale)).To(Succeed())
+		stored := &v1alpha1.NamespaceLabel{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nsLabel), stored)).To(Succeed())
+		Expect(stored.Spec.Labels).To(HaveKeyWithValue("team", "b"))
+		Expect(stored.Finalizers).To(ConsistOf("example.com/other", namespaceLabelFinalizer))
+		Expect(stale.Spec.Labels).To(HaveKeyWithValue("team", "b"))
+	})
+
+	It("should retry the patch on conflicts", func() {
+		conflicts := 2
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nsLabel).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return apierrors.NewConflict(schema.GroupResource{Resource: "namespacelabels"}, obj.GetName(), nil)
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		Expect(EnsureFinalizer(ctx, c, nsLabel)).To(Succeed())
+		Expect(conflicts).To(BeZero())
+		stored := &v1alpha1.NamespaceLabel{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nsLabel), stored)).To(Succeed())
+		Expect(stored.Finalizers).To(ConsistOf(namespaceLabelFinalizer))
+	})
+
+	It("should return NotFound for a deleted object", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		nsLabel.Finalizers = []string{namespaceLabelFinalizer}
+		nsLabel.ResourceVersion = "1"
+		Expect(apierrors.IsNotFound(RemoveFinalizer(ctx, c, nsLabel))).To(BeTrue())
+	})
+})
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Sources of the protected labels reported by ProtectedLabelsFetchFailures.
+const (
+	SourceConfigMap = "configmap"
+	SourcePolicy    = "policy"
+)
+
+// TargetNamespaceLabel is the metric label naming the namespace a metric is about. It is not called namespace,
+// since Prometheus sets the namespace label of scraped metrics to the namespace of the controller.
+const TargetNamespaceLabel = "target_namespace"
+
+// Results of namespace updates reported by NamespaceUpdateDuration.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	// DriftCorrections counts the namespace labels restored after being changed outside of the controller.
+	DriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "namespacelabel_drift_corrections_total",
+		Help: "Number of managed namespace labels restored after being modified or removed outside of the controller.",
+	}, []string{TargetNamespaceLabel})
+
+	// ManagedLabels is the number of labels the controller manages on each namespace.
+	ManagedLabels = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "namespacelabel_managed_labels",
+		Help: "Number of namespace labels managed by the controller.",
+	}, []string{TargetNamespaceLabel})
+
+	// ManagedAnnotations is the number of annotations the controller manages on each namespace.
+	ManagedAnnotations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "namespacelabel_managed_annotations",
+		Help: "Number of namespace annotations managed by the controller.",
+	}, []string{TargetNamespaceLabel})
+
+	// RejectedLabels is the number of labels and annotations of each NamespaceLabel rejected for a reason.
+	RejectedLabels = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "namespacelabel_rejected_labels",
+		Help: "Number of labels and annotations of a NamespaceLabel rejected by validation, by reason.",
+	}, []string{TargetNamespaceLabel, "name", "reason"})
+
+	// Conflicts is the number of labels and annotations of each NamespaceLabel won by another label source.
+	Conflicts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "namespacelabel_conflicts",
+		Help: "Number of labels and annotations of a NamespaceLabel in conflict with other label sources.",
+	}, []string{TargetNamespaceLabel, "name"})
+
+	// NamespaceUpdateDuration is the latency of writing labels to a namespace.
+	NamespaceUpdateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "namespacelabel_namespace_update_duration_seconds",
+		Help:    "Latency of applying labels and annotations to a namespace.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"result"})
+
+	// ProtectedLabelsFetchFailures counts the failures to read the protected labels ConfigMap and policies.
+	ProtectedLabelsFetchFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "namespacelabel_protected_labels_fetch_failures_total",
+		Help: "Number of failures to fetch the protected labels, by source.",
+	}, []string{"source"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		DriftCorrections,
+		ManagedLabels,
+		ManagedAnnotations,
+		RejectedLabels,
+		Conflicts,
+		NamespaceUpdateDuration,
+		ProtectedLabelsFetchFailures,
+	)
+}
+
+// RecordNamespaceLabel replaces the rejected labels and conflicts reported for a NamespaceLabel.
+func RecordNamespaceLabel(namespace, name string, rejectedByReason map[string]int, conflicts int) {
+	RejectedLabels.DeletePartialMatch(prometheus.Labels{TargetNamespaceLabel: namespace, "name": name})
+	for reason, count := range rejectedByReason {
+		RejectedLabels.WithLabelValues(namespace, name, reason).Set(float64(count))
+	}
+	Conflicts.WithLabelValues(namespace, name).Set(float64(conflicts))
+}
+
+// ForgetNamespaceLabel removes the metrics of a deleted NamespaceLabel.
+func ForgetNamespaceLabel(namespace, name string) {
+	RejectedLabels.DeletePartialMatch(prometheus.Labels{TargetNamespaceLabel: namespace, "name": name})
+	Conflicts.DeleteLabelValues(namespace, name)
+}
+
+// ForgetNamespace removes the metrics of a deleted namespace.
+func ForgetNamespace(namespace string) {
+	ManagedLabels.DeleteLabelValues(namespace)
+	ManagedAnnotations.DeleteLabelValues(namespace)
+	DriftCorrections.DeleteLabelValues(namespace)
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Metrics", func() {
+	BeforeEach(func() {
+		DriftCorrections.Reset()
+		ManagedLabels.Reset()
+		ManagedAnnotations.Reset()
+		RejectedLabels.Reset()
+		Conflicts.Reset()
+		NamespaceUpdateDuration.Reset()
+		ProtectedLabelsFetchFailures.Reset()
+	})
+
+	It("should report the namespace of NamespaceLabels as target_namespace", func() {
+		RecordNamespaceLabel("team-a", "labels", map[string]int{"ProtectedLabel": 2}, 1)
+
+		Expect(testutil.CollectAndCompare(RejectedLabels, strings.NewReader(`
+# HELP namespacelabel_rejected_labels Number of labels and annotations of a NamespaceLabel rejected by validation, by reason.
+# TYPE namespacelabel_rejected_labels gauge
+namespacelabel_rejected_labels{name="labels",reason="ProtectedLabel",target_namespace="team-a"} 2
+`))).To(Succeed())
+		Expect(testutil.CollectAndCompare(Conflicts, strings.NewReader(`
+# HELP namespacelabel_conflicts Number of labels and annotations of a NamespaceLabel in conflict with other label sources.
+# TYPE namespacelabel_conflicts gauge
+namespacelabel_conflicts{name="labels",target_namespace="team-a"} 1
+`))).To(Succeed())
+
+		By("replacing the rejected labels of the NamespaceLabel")
+		RecordNamespaceLabel("team-a", "labels", map[string]int{"InvalidValue": 1}, 0)
+		Expect(testutil.ToFloat64(RejectedLabels.WithLabelValues("team-a", "labels", "InvalidValue"))).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(RejectedLabels)).To(Equal(1))
+
+		By("forgetting the NamespaceLabel")
+		ForgetNamespaceLabel("team-a", "labels")
+		Expect(testutil.CollectAndCount(RejectedLabels)).To(BeZero())
+		Expect(testutil.CollectAndCount(Conflicts)).To(BeZero())
+	})
+
+	It("should count drift corrections per target namespace and forget them with the namespace", func() {
+		DriftCorrections.WithLabelValues("team-a").Add(2)
+		DriftCorrections.WithLabelValues("team-b").Inc()
+		ManagedLabels.WithLabelValues("team-a").Set(3)
+
+		Expect(testutil.CollectAndCompare(DriftCorrections, strings.NewReader(`
+# HELP namespacelabel_drift_corrections_total Number of managed namespace labels restored after being modified or removed outside of the controller.
+# TYPE namespacelabel_drift_corrections_total counter
+namespacelabel_drift_corrections_total{target_namespace="team-a"} 2
+namespacelabel_drift_corrections_total{target_namespace="team-b"} 1
+`))).To(Succeed())
+
+		ForgetNamespace("team-a")
+		Expect(testutil.CollectAndCount(DriftCorrections)).To(Equal(1))
+		Expect(testutil.CollectAndCount(ManagedLabels)).To(BeZero())
+	})
+
+	It("should count protected label fetch failures by source", func() {
+		ProtectedLabelsFetchFailures.WithLabelValues(SourceConfigMap).Inc()
+		ProtectedLabelsFetchFailures.WithLabelValues(SourcePolicy).Add(2)
+
+		Expect(testutil.CollectAndCompare(ProtectedLabelsFetchFailures, strings.NewReader(`
+# HELP namespacelabel_protected_labels_fetch_failures_total Number of failures to fetch the protected labels, by source.
+# TYPE namespacelabel_protected_labels_fetch_failures_total counter
+namespacelabel_protected_labels_fetch_failures_total{source="configmap"} 1
+namespacelabel_protected_labels_fetch_failures_total{source="policy"} 2
+`))).To(Succeed())
+	})
+
+	It("should observe namespace update latencies by result", func() {
+		NamespaceUpdateDuration.WithLabelValues(ResultSuccess).Observe(0.02)
+		NamespaceUpdateDuration.WithLabelValues(ResultSuccess).Observe(3)
+		NamespaceUpdateDuration.WithLabelValues(ResultError).Observe(0.5)
+
+		success := histogram(NamespaceUpdateDuration.WithLabelValues(ResultSuccess))
+		Expect(success.GetSampleCount()).To(Equal(uint64(2)))
+		Expect(success.GetSampleSum()).To(BeNumerically("~", 3.02))
+		Expect(success.GetBucket()).To(ContainElement(SatisfyAll(
+			HaveField("GetUpperBound()", 0.025),
+			HaveField("GetCumulativeCount()", uint64(1)),
+		)))
+		Expect(histogram(NamespaceUpdateDuration.WithLabelValues(ResultError)).GetSampleCount()).To(Equal(uint64(1)))
+	})
+})
+
+// histogram returns the current state of the histogram.
+func histogram(observer prometheus.Observer) *dto.Histogram {
+	metric := &dto.Metric{}
+	Expect(observer.(prometheus.Metric).Write(metric)).To(Succeed())
+	return metric.GetHistogram()
+}
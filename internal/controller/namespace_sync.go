@@ -0,0 +1,531 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/metrics"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+	"github.com/oshribelay/namespace-label/internal/controller/utils"
+)
+
+const (
+	// managedLabelsAnnotation records on the Namespace which label keys are owned by the controller.
+	managedLabelsAnnotation = "namespacelabel.dana.io/managed-labels"
+	// managedAnnotationsAnnotation records on the Namespace which annotation keys are owned by the controller.
+	managedAnnotationsAnnotation = "namespacelabel.dana.io/managed-annotations"
+
+	// fieldManager is the server-side apply field manager owning the labels applied to namespaces.
+	fieldManager = "namespace-label"
+	// retainedFieldManager owns protected labels that are released by the fieldManager but
+	// retained on the namespace by the protected label action.
+	retainedFieldManager = "namespace-label-retained"
+)
+
+// namespaceKeys describes one kind of namespace metadata managed by the controller, labels or annotations.
+// Both kinds are merged, protected, tracked and applied the same way.
+type namespaceKeys struct {
+	// managedAnnotation records on the namespace which keys of the kind are owned by the controller.
+	managedAnnotation string
+	// merge merges the keys of the kind declared by the label sources of the namespace.
+	merge func(namespace string, sources []resources.LabelSource, protectedLabels *policy.ProtectedLabels, strategy resources.ConflictStrategy) resources.KeyMerge
+	// isProtected reports whether a key of the kind is protected in the namespace.
+	isProtected func(protectedLabels *policy.ProtectedLabels, namespace, key string) bool
+	// pick returns the keys of the kind out of object metadata.
+	pick func(labels, annotations map[string]string) map[string]string
+	// applied returns the keys of the kind last applied for a NamespaceLabel.
+	applied func(status namespacelabelv1alpha1.NamespaceLabelStatus) map[string]string
+}
+
+var (
+	labelKeys = namespaceKeys{
+		managedAnnotation: managedLabelsAnnotation,
+		merge:             resources.MergeLabels,
+		isProtected:       (*policy.ProtectedLabels).IsProtected,
+		pick:              func(labels, _ map[string]string) map[string]string { return labels },
+		applied: func(status namespacelabelv1alpha1.NamespaceLabelStatus) map[string]string {
+			return status.AppliedLabels
+		},
+	}
+	annotationKeys = namespaceKeys{
+		managedAnnotation: managedAnnotationsAnnotation,
+		merge:             resources.MergeAnnotations,
+		isProtected:       (*policy.ProtectedLabels).IsProtectedAnnotation,
+		pick:              func(_, annotations map[string]string) map[string]string { return annotations },
+		applied: func(status namespacelabelv1alpha1.NamespaceLabelStatus) map[string]string {
+			return status.AppliedAnnotations
+		},
+	}
+)
+
+// namespaceMerge is the desired labels and annotations of a namespace.
+type namespaceMerge struct {
+	labels      resources.KeyMerge
+	annotations resources.KeyMerge
+}
+
+// keysPlan is the planned update of one kind of namespace metadata.
+type keysPlan struct {
+	merge resources.KeyMerge
+	// retained are the managed keys that became protected and stay on the namespace unmanaged.
+	retained map[string]string
+	// drifted are the managed keys that were changed outside of the controller.
+	drifted []string
+	// reclaimed are the desired managed keys whose value was changed by another field manager, with
+	// their desired value. That field manager owns them now, so they are taken back by force.
+	reclaimed map[string]string
+}
+
+// plan merges the keys of the kind declared by the label sources and determines which managed keys
+// are retained and which drifted.
+func (k namespaceKeys) plan(namespace corev1.Namespace, sources []resources.LabelSource, namespaceLabelList namespacelabelv1alpha1.NamespaceLabelList, protectedLabels *policy.ProtectedLabels, strategy resources.ConflictStrategy, action resources.ProtectedLabelAction) keysPlan {
+	plan := keysPlan{
+		merge:    k.merge(namespace.Name, sources, protectedLabels, strategy),
+		retained: make(map[string]string),
+	}
+	current := k.pick(namespace.Labels, namespace.Annotations)
+	managedKeys := utils.ParseManagedKeys(namespace.Annotations[k.managedAnnotation])
+	for key := range managedKeys {
+		if desired, exists := plan.merge.Values[key]; exists {
+			if value, exists := current[key]; exists && value != desired {
+				if plan.reclaimed == nil {
+					plan.reclaimed = make(map[string]string)
+				}
+				plan.reclaimed[key] = desired
+			}
+			continue
+		}
+		if k.isProtected(protectedLabels, namespace.Name, key) && action != resources.ProtectedLabelActionRemove {
+			if value, exists := current[key]; exists {
+				plan.retained[key] = value
+			}
+		}
+	}
+	plan.drifted = k.driftedKeys(current, managedKeys, plan.merge.Values, namespaceLabelList)
+	return plan
+}
+
+// driftedKeys returns the sorted managed keys that were removed from the namespace, or changed
+// away from the value last applied by the controller, by someone else.
+func (k namespaceKeys) driftedKeys(current map[string]string, managedKeys map[string]bool, desired map[string]string, namespaceLabelList namespacelabelv1alpha1.NamespaceLabelList) []string {
+	var drifted []string
+	for key := range desired {
+		if !managedKeys[key] {
+			continue
+		}
+		value, exists := current[key]
+		if !exists {
+			drifted = append(drifted, key)
+			continue
+		}
+		for _, label := range namespaceLabelList.Items {
+			// suspended NamespaceLabels keep the current values instead of restoring them
+			if label.Spec.Suspend {
+				continue
+			}
+			if applied, ok := k.applied(label.Status)[key]; ok && applied != value {
+				drifted = append(drifted, key)
+				break
+			}
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+// namespaceSyncer writes the labels and annotations merged from the NamespaceLabels and
+// ClusterNamespaceLabels of a namespace to the namespace. It is shared by the reconcilers of both kinds.
+type namespaceSyncer struct {
+	client.Client
+	conflictStrategy     resources.ConflictStrategy
+	protectedLabelAction resources.ProtectedLabelAction
+	// recorder records events on the namespace for the labels changed by the sync, it may be nil.
+	recorder record.EventRecorder
+	// dryRun plans the changes of every label source without writing them to the namespace.
+	dryRun bool
+}
+
+// namespacePlan is the planned update of the labels and annotations of a namespace.
+type namespacePlan struct {
+	labels      keysPlan
+	annotations keysPlan
+	// desiredAnnotations are the annotations to apply, including the managed keys annotations.
+	desiredAnnotations map[string]string
+	// applied is the configuration currently applied by the fieldManager.
+	applied *corev1ac.NamespaceApplyConfiguration
+	// namespaceLabels are the NamespaceLabels of the namespace.
+	namespaceLabels []namespacelabelv1alpha1.NamespaceLabel
+}
+
+// merge returns the desired labels and annotations of the plan.
+func (p namespacePlan) merge() namespaceMerge {
+	return namespaceMerge{labels: p.labels.merge, annotations: p.annotations.merge}
+}
+
+// upToDate reports whether the namespace already holds the planned labels and annotations.
+func (p namespacePlan) upToDate() bool {
+	return utils.EqualLabels(p.applied.Labels, p.labels.merge.Values) && utils.EqualLabels(p.applied.Annotations, p.desiredAnnotations) &&
+		len(p.labels.retained) == 0 && len(p.annotations.retained) == 0
+}
+
+// planNamespaceLabels plans the labels and annotations of the namespace according to every NamespaceLabel
+// in the namespace and every ClusterNamespaceLabel selecting it. Dry run NamespaceLabels keep the labels
+// they applied last, except for the NamespaceLabel named preview whose spec is planned.
+func (s namespaceSyncer) planNamespaceLabels(ctx context.Context, namespace corev1.Namespace, protectedLabels *policy.ProtectedLabels, preview string) (namespacePlan, error) {
+	logger := log.FromContext(ctx)
+
+	namespaceLabelList := namespacelabelv1alpha1.NamespaceLabelList{}
+	if err := s.List(ctx, &namespaceLabelList, client.InNamespace(namespace.Name)); err != nil {
+		logger.Error(err, "Failed to fetch NamespaceLabels")
+		return namespacePlan{}, err
+	}
+	for i := range namespaceLabelList.Items {
+		if s.dryRun || namespaceLabelList.Items[i].Name == preview {
+			namespaceLabelList.Items[i].Spec.DryRun = false
+		}
+	}
+	clusterNamespaceLabelList := namespacelabelv1alpha1.ClusterNamespaceLabelList{}
+	if err := s.List(ctx, &clusterNamespaceLabelList); err != nil {
+		logger.Error(err, "Failed to fetch ClusterNamespaceLabels")
+		return namespacePlan{}, err
+	}
+	sources := append(resources.NamespaceLabelSources(namespaceLabelList.Items, namespace, protectedLabels),
+		resources.ClusterNamespaceLabelSources(clusterNamespaceLabelList.Items, namespace)...)
+	plan := namespacePlan{
+		labels:      labelKeys.plan(namespace, sources, namespaceLabelList, protectedLabels, s.conflictStrategy, s.protectedLabelAction),
+		annotations: annotationKeys.plan(namespace, sources, namespaceLabelList, protectedLabels, s.conflictStrategy, s.protectedLabelAction),
+	}
+
+	plan.desiredAnnotations = make(map[string]string, len(plan.annotations.merge.Values)+2)
+	for key, value := range plan.annotations.merge.Values {
+		plan.desiredAnnotations[key] = value
+	}
+	if managed := utils.FormatManagedKeys(plan.labels.merge.Values); managed != "" {
+		plan.desiredAnnotations[managedLabelsAnnotation] = managed
+	}
+	if managed := utils.FormatManagedKeys(plan.annotations.merge.Values); managed != "" {
+		plan.desiredAnnotations[managedAnnotationsAnnotation] = managed
+	}
+
+	applied, err := corev1ac.ExtractNamespace(&namespace, fieldManager)
+	if err != nil {
+		logger.Error(err, "Failed to extract the labels applied to the namespace")
+		return namespacePlan{}, err
+	}
+	plan.applied = applied
+	plan.namespaceLabels = namespaceLabelList.Items
+	return plan, nil
+}
+
+// updateNamespaceLabels updates the labels and annotations of the namespace according to every
+// NamespaceLabel in the namespace and every ClusterNamespaceLabel selecting it.
+// They are written with server-side apply under the fieldManager field manager, so the API server
+// tracks the ownership of every key: keys added by other tools or users are left untouched, keys
+// dropped from the applied configuration are released and changes conflicting with another field
+// manager are returned as errors, except for managed keys changed by another field manager, which
+// are taken back. Managed keys that became protected are released or removed
+// according to the protected label action. It returns the desired labels and annotations of the
+// namespace together with the owner of each of them. Nothing is written in dry run mode.
+func (s namespaceSyncer) updateNamespaceLabels(ctx context.Context, namespace corev1.Namespace, protectedLabels *policy.ProtectedLabels) (namespaceMerge, error) {
+	logger := log.FromContext(ctx)
+
+	plan, err := s.planNamespaceLabels(ctx, namespace, protectedLabels, "")
+	if err != nil {
+		return namespaceMerge{}, err
+	}
+	labels, annotations := plan.labels, plan.annotations
+	merge := plan.merge()
+	if namespaceTerminating(namespace) {
+		logger.Info("Namespace is terminating, skipping label writes", "namespace", namespace.Name)
+		return merge, nil
+	}
+	if plan.upToDate() {
+		logger.Info("Namespace label is already up to date no changes needed")
+		recordManagedKeys(namespace.Name, merge)
+		return merge, nil
+	}
+	if s.dryRun {
+		logger.Info("Dry run, not updating the namespace", "namespace", namespace.Name,
+			"changes", describePlan(plannedChanges(namespace, plan)))
+		return merge, nil
+	}
+
+	if len(labels.drifted) > 0 || len(annotations.drifted) > 0 {
+		logger.Info("Restoring managed labels modified outside of the controller", "namespace", namespace.Name,
+			"labels", labels.drifted, "annotations", annotations.drifted)
+	}
+
+	// retained keys are handed over to the retainedFieldManager before they are released, so they
+	// stay on the namespace once they are no longer applied
+	if err := s.applyRetainedKeys(ctx, &namespace, labels, annotations, true); err != nil {
+		logger.Error(err, "Failed to retain protected namespace labels")
+		return namespaceMerge{}, err
+	}
+
+	if len(labels.reclaimed) > 0 || len(annotations.reclaimed) > 0 {
+		// only the reclaimed keys are added to the applied configuration, so no other key is forced
+		reclaim := corev1ac.Namespace(namespace.Name).
+			WithLabels(plan.applied.Labels).WithLabels(labels.reclaimed).
+			WithAnnotations(plan.applied.Annotations).WithAnnotations(annotations.reclaimed)
+		if err := s.applyNamespace(ctx, &namespace, reclaim, fieldManager, client.ForceOwnership); err != nil {
+			logger.Error(err, "Failed to reclaim the managed namespace labels")
+			return namespaceMerge{}, err
+		}
+	}
+
+	namespaceApply := corev1ac.Namespace(namespace.Name).WithLabels(labels.merge.Values).WithAnnotations(plan.desiredAnnotations)
+	if err := s.applyNamespace(ctx, &namespace, namespaceApply, fieldManager); err != nil {
+		logger.Error(err, "Failed to apply the namespace labels")
+		return namespaceMerge{}, err
+	}
+
+	if err := s.applyRetainedKeys(ctx, &namespace, keysPlan{merge: labels.merge}, keysPlan{merge: annotations.merge}, false); err != nil {
+		logger.Error(err, "Failed to release retained namespace labels")
+		return namespaceMerge{}, err
+	}
+
+	if drifted := len(labels.drifted) + len(annotations.drifted); drifted > 0 {
+		metrics.DriftCorrections.WithLabelValues(namespace.Name).Add(float64(drifted))
+	}
+	s.recordNamespaceEvents(&namespace, plan)
+	recordManagedKeys(namespace.Name, merge)
+	logger.Info("Updated Namespace Successfully", "namespace", namespace.Name)
+	return merge, nil
+}
+
+// dryRunNamespaceLabels plans the changes the NamespaceLabel named preview makes to the namespace
+// together with the changes of the other label sources, without writing them.
+func (s namespaceSyncer) dryRunNamespaceLabels(ctx context.Context, namespace corev1.Namespace, protectedLabels *policy.ProtectedLabels, preview string) (namespaceMerge, *namespacelabelv1alpha1.NamespaceLabelPlan, error) {
+	plan, err := s.planNamespaceLabels(ctx, namespace, protectedLabels, preview)
+	if err != nil {
+		return namespaceMerge{}, nil, err
+	}
+	return plan.merge(), plannedChanges(namespace, plan), nil
+}
+
+// plannedChanges returns the labels and annotations the plan adds to, changes on and removes from the namespace.
+func plannedChanges(namespace corev1.Namespace, plan namespacePlan) *namespacelabelv1alpha1.NamespaceLabelPlan {
+	return &namespacelabelv1alpha1.NamespaceLabelPlan{
+		Labels:      plannedKeyChanges(namespace.Labels, plan.applied.Labels, plan.labels),
+		Annotations: plannedKeyChanges(namespace.Annotations, plan.applied.Annotations, plan.annotations),
+	}
+}
+
+// plannedKeyChanges compares the planned keys with the current keys of the namespace. Applied keys that
+// are no longer desired are removed, unless they are retained.
+func plannedKeyChanges(current, applied map[string]string, plan keysPlan) namespacelabelv1alpha1.KeyChanges {
+	changes := namespacelabelv1alpha1.KeyChanges{}
+	for key, value := range plan.merge.Values {
+		currentValue, exists := current[key]
+		switch {
+		case !exists:
+			if changes.Added == nil {
+				changes.Added = make(map[string]string)
+			}
+			changes.Added[key] = value
+		case currentValue != value:
+			if changes.Changed == nil {
+				changes.Changed = make(map[string]string)
+			}
+			changes.Changed[key] = value
+		}
+	}
+	for key := range applied {
+		if key == managedLabelsAnnotation || key == managedAnnotationsAnnotation {
+			continue
+		}
+		if _, desired := plan.merge.Values[key]; desired {
+			continue
+		}
+		if _, retained := plan.retained[key]; !retained {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+// describePlan summarizes the planned changes, such as "add labels a; remove annotations b".
+func describePlan(plan *namespacelabelv1alpha1.NamespaceLabelPlan) string {
+	var parts []string
+	for _, change := range []struct {
+		action              string
+		labels, annotations []string
+	}{
+		{"add", sortedKeys(plan.Labels.Added), sortedKeys(plan.Annotations.Added)},
+		{"change", sortedKeys(plan.Labels.Changed), sortedKeys(plan.Annotations.Changed)},
+		{"remove", plan.Labels.Removed, plan.Annotations.Removed},
+	} {
+		if keys := describeKeys(change.labels, change.annotations); keys != "" {
+			parts = append(parts, change.action+" "+keys)
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sortedKeys returns the sorted keys of the map.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordNamespaceEvents records one event per kind of change on the namespace, listing the labels and
+// annotations applied, removed and restored by the sync compared to the previously applied configuration.
+// The restored keys are recorded on the NamespaceLabels providing them too.
+func (s namespaceSyncer) recordNamespaceEvents(namespace *corev1.Namespace, plan namespacePlan) {
+	previous, labels, annotations := plan.applied, plan.labels, plan.annotations
+	previousAnnotations := make(map[string]string, len(previous.Annotations))
+	for key, value := range previous.Annotations {
+		if key != managedLabelsAnnotation && key != managedAnnotationsAnnotation {
+			previousAnnotations[key] = value
+		}
+	}
+	changedLabels, removedLabels := keyChanges(previous.Labels, labels.merge.Values)
+	changedAnnotations, removedAnnotations := keyChanges(previousAnnotations, annotations.merge.Values)
+	recordChanges(s.recorder, namespace, corev1.EventTypeNormal, namespacelabelv1alpha1.EventReasonLabelsApplied,
+		"applied", changedLabels, changedAnnotations)
+	recordChanges(s.recorder, namespace, corev1.EventTypeNormal, namespacelabelv1alpha1.EventReasonLabelsRemoved,
+		"removed", removedLabels, removedAnnotations)
+	recordChanges(s.recorder, namespace, corev1.EventTypeWarning, namespacelabelv1alpha1.EventReasonDriftCorrected,
+		"restored managed keys modified outside of the controller:", labels.drifted, annotations.drifted)
+	for i := range plan.namespaceLabels {
+		owner := resources.NamespaceLabelOwner(plan.namespaceLabels[i].Name)
+		recordChanges(s.recorder, &plan.namespaceLabels[i], corev1.EventTypeWarning, namespacelabelv1alpha1.EventReasonDriftCorrected,
+			"restored managed keys modified outside of the controller:",
+			ownedKeys(labels.drifted, labels.merge, owner), ownedKeys(annotations.drifted, annotations.merge, owner))
+	}
+}
+
+// ownedKeys returns the keys whose value is provided by the owner according to the merge.
+func ownedKeys(keys []string, merge resources.KeyMerge, owner string) []string {
+	var owned []string
+	for _, key := range keys {
+		if merge.Owners[key] == owner {
+			owned = append(owned, key)
+		}
+	}
+	return owned
+}
+
+// retainKeys hands the given labels and annotations over to the retainedFieldManager, with their current
+// value on the namespace, so that they stay on the namespace once the fieldManager releases them.
+func (s namespaceSyncer) retainKeys(ctx context.Context, namespace corev1.Namespace, labels, annotations map[string]string) error {
+	if s.dryRun || namespaceTerminating(namespace) {
+		return nil
+	}
+	labelsPlan := keysPlan{retained: make(map[string]string, len(labels))}
+	for key := range labels {
+		if value, exists := namespace.Labels[key]; exists {
+			labelsPlan.retained[key] = value
+		}
+	}
+	annotationsPlan := keysPlan{retained: make(map[string]string, len(annotations))}
+	for key := range annotations {
+		if value, exists := namespace.Annotations[key]; exists {
+			annotationsPlan.retained[key] = value
+		}
+	}
+	log.FromContext(ctx).Info("Retaining namespace labels", "namespace", namespace.Name,
+		"labels", utils.FormatManagedKeys(labelsPlan.retained), "annotations", utils.FormatManagedKeys(annotationsPlan.retained))
+	return s.applyRetainedKeys(ctx, &namespace, labelsPlan, annotationsPlan, true)
+}
+
+// applyRetainedKeys adds the retained labels and annotations of the plans to the keys owned by the
+// retainedFieldManager and releases the keys that are desired again. When beforeApply is true, only
+// desired keys whose value differs are released, since the fieldManager cannot take them over without
+// a conflict; the others are released once the fieldManager owns them, so they never leave the namespace.
+func (s namespaceSyncer) applyRetainedKeys(ctx context.Context, namespace *corev1.Namespace, labels, annotations keysPlan, beforeApply bool) error {
+	retained, err := corev1ac.ExtractNamespace(namespace, retainedFieldManager)
+	if err != nil {
+		return err
+	}
+
+	retainedLabels := retainedValues(retained.Labels, labels, beforeApply)
+	retainedAnnotations := retainedValues(retained.Annotations, annotations, beforeApply)
+	if utils.EqualLabels(retainedLabels, retained.Labels) && utils.EqualLabels(retainedAnnotations, retained.Annotations) {
+		return nil
+	}
+	return s.applyNamespace(ctx, namespace, corev1ac.Namespace(namespace.Name).
+		WithLabels(retainedLabels).WithAnnotations(retainedAnnotations), retainedFieldManager)
+}
+
+// retainedValues returns the keys the retainedFieldManager should own, see applyRetainedKeys.
+func retainedValues(current map[string]string, plan keysPlan, beforeApply bool) map[string]string {
+	values := make(map[string]string, len(current)+len(plan.retained))
+	for key, value := range current {
+		values[key] = value
+	}
+	for key, value := range plan.retained {
+		values[key] = value
+	}
+	for key, value := range plan.merge.Values {
+		if retainedValue, exists := values[key]; exists && (!beforeApply || retainedValue != value) {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+// applyNamespace server-side applies the configuration to the namespace as the given field manager and
+// refreshes the namespace. Ownership of fields owned by other field managers is only forced with the
+// client.ForceOwnership option.
+func (s namespaceSyncer) applyNamespace(ctx context.Context, namespace *corev1.Namespace, config *corev1ac.NamespaceApplyConfiguration, manager string, opts ...client.PatchOption) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = s.Patch(ctx, namespace, client.RawPatch(types.ApplyPatchType, data), append(opts, client.FieldOwner(manager))...)
+	result := metrics.ResultSuccess
+	if err != nil {
+		result = metrics.ResultError
+	}
+	metrics.NamespaceUpdateDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// namespaceTerminating reports whether the namespace is being deleted, its labels are not written anymore.
+func namespaceTerminating(namespace corev1.Namespace) bool {
+	return !namespace.DeletionTimestamp.IsZero() || namespace.Status.Phase == corev1.NamespaceTerminating
+}
+
+// recordManagedKeys reports the number of labels and annotations managed on the namespace.
+func recordManagedKeys(namespace string, merge namespaceMerge) {
+	metrics.ManagedLabels.WithLabelValues(namespace).Set(float64(len(merge.labels.Values)))
+	metrics.ManagedAnnotations.WithLabelValues(namespace).Set(float64(len(merge.annotations.Values)))
+}
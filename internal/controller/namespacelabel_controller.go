@@ -18,37 +18,60 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 
 	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
 	"github.com/oshribelay/namespace-label/internal/controller/finalizer"
+	"github.com/oshribelay/namespace-label/internal/controller/metrics"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
 	"github.com/oshribelay/namespace-label/internal/controller/resources"
-	"github.com/oshribelay/namespace-label/internal/controller/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // NamespaceLabelReconciler reconciles a NamespaceLabel object
 type NamespaceLabelReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
-}
 
-const (
-	configMapName      = "namespace-label-protected-labels"
-	configMapNamespace = "namespace-label-system"
-)
+	// ConflictStrategy decides which NamespaceLabel wins when several set the same label to different values.
+	ConflictStrategy resources.ConflictStrategy
+
+	// ProtectedLabelAction decides whether managed labels that become protected are removed from the namespace.
+	ProtectedLabelAction resources.ProtectedLabelAction
+
+	// ProtectedLabelsSource configures how the protected labels are loaded.
+	ProtectedLabelsSource policy.Source
+
+	// Recorder records events on NamespaceLabels.
+	Recorder record.EventRecorder
+
+	// DryRun reconciles every NamespaceLabel as a dry run, planning its changes without writing to namespaces.
+	DryRun bool
+}
 
 // +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=namespacelabels,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=namespacelabels/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=namespacelabels/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=protectedlabelpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=clusternamespacelabels,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -65,119 +88,292 @@ func (r *NamespaceLabelReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	if !nsLabel.DeletionTimestamp.IsZero() && nsLabel.Annotations[namespacelabelv1alpha1.ForceFinalizeAnnotation] == "true" {
+		logger.Info("Force finalizing NamespaceLabel without updating the namespace")
+		if err := finalizer.RemoveFinalizer(ctx, r.Client, &nsLabel); err != nil {
+			logger.Error(err, "Failed to remove finalizer")
+			return ctrl.Result{}, err
+		}
+		metrics.ForgetNamespaceLabel(nsLabel.Namespace, nsLabel.Name)
+		return ctrl.Result{}, nil
+	}
+
 	namespace := corev1.Namespace{}
+	namespaceGone := false
 	if err := r.Get(ctx, types.NamespacedName{Name: nsLabel.Namespace}, &namespace); err != nil {
-		logger.Error(err, "Failed to fetch Namespace")
-		return ctrl.Result{}, err
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to fetch Namespace")
+			return ctrl.Result{}, err
+		}
+		namespaceGone = true
 	}
 
-	namespaceLabelList := namespacelabelv1alpha1.NamespaceLabelList{}
-	if err := r.List(ctx, &namespaceLabelList, client.InNamespace(namespace.Name)); err != nil {
-		return ctrl.Result{}, err
+	if namespaceGone {
+		metrics.ForgetNamespace(nsLabel.Namespace)
+		return ctrl.Result{}, r.releaseNamespaceLabel(ctx, &nsLabel, namespacelabelv1alpha1.ReasonNamespaceNotFound,
+			fmt.Sprintf("namespace %s not found, no labels are written", nsLabel.Namespace))
+	}
+	if namespaceTerminating(namespace) {
+		return ctrl.Result{}, r.releaseNamespaceLabel(ctx, &nsLabel, namespacelabelv1alpha1.ReasonNamespaceTerminating,
+			fmt.Sprintf("namespace %s is terminating, no labels are written", nsLabel.Namespace))
 	}
 
-	protectedLabelsConfigMap := corev1.ConfigMap{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: configMapNamespace, Name: configMapName}, &protectedLabelsConfigMap); err != nil {
-		logger.Error(err, "get Failed to fetch protected labels ConfigMap")
-		return ctrl.Result{}, err
+	// deletion does not depend on the spec, so invalid NamespaceLabels can always be deleted
+	if !nsLabel.DeletionTimestamp.IsZero() {
+		protectedLabels, err := r.ProtectedLabelsSource.Load(ctx, r.Client)
+		if err != nil {
+			// an unavailable ConfigMap or policy must not keep the NamespaceLabel terminating forever
+			logger.Error(err, "Failed to load protected labels, releasing the labels with the default protected labels")
+			protectedLabels = policy.Defaults()
+		}
+		if err := r.handleDeletion(ctx, namespace, nsLabel, protectedLabels, logger); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
 	}
-	protectedPrefixes := protectedLabelsConfigMap.Data
 
-	if err := resources.ValidateNamespaceLabel(nsLabel.Spec.Labels, protectedPrefixes); err != nil {
+	protectedLabels, err := r.ProtectedLabelsSource.Load(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to load protected labels")
+		if statusErr := r.updateStatus(ctx, &nsLabel, labelSync{
+			err:           err,
+			failureReason: namespacelabelv1alpha1.ReasonProtectedLabelsUnavailable,
+		}); statusErr != nil {
+			logger.Error(statusErr, "Failed to update NamespaceLabel status")
+		}
 		return ctrl.Result{}, err
 	}
 
-	if !nsLabel.DeletionTimestamp.IsZero() {
-		if err := r.handleDeletion(ctx, req, namespace, nsLabel, protectedPrefixes, logger); err != nil {
+	if nsLabel.Spec.Suspend {
+		logger.Info("Reconciliation is suspended, keeping the applied labels")
+		if err := r.updateStatus(ctx, &nsLabel, labelSync{suspended: true}); err != nil {
+			logger.Error(err, "Failed to update NamespaceLabel status")
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
 
+	rejectedLabels := resources.RejectedLabels(nsLabel.Namespace, nsLabel.Spec.Labels, protectedLabels)
+	rejectedAnnotations := resources.RejectedAnnotations(nsLabel.Namespace, nsLabel.Spec.Annotations, protectedLabels)
+	if errs := resources.ValidateNamespaceLabel(nsLabel.Namespace, nsLabel.Spec.Labels, nsLabel.Spec.Annotations, protectedLabels); len(errs) > 0 {
+		if nsLabel.Spec.BestEffort() {
+			// the invalid keys are skipped by the merge, the valid ones are applied below
+			logger.Info("Skipping invalid labels", "errors", errs.ToAggregate().Error())
+		} else {
+			err := errs.ToAggregate()
+			// keep the namespace in sync so that labels which became protected are handled
+			// according to the protected label action
+			if _, syncErr := r.namespaceSyncer().updateNamespaceLabels(ctx, namespace, protectedLabels); syncErr != nil {
+				logger.Error(syncErr, "Failed to sync namespace labels")
+			}
+			if statusErr := r.updateStatus(ctx, &nsLabel, labelSync{
+				rejectedLabels:      rejectedLabels,
+				rejectedAnnotations: rejectedAnnotations,
+				err:                 err,
+				failureReason:       namespacelabelv1alpha1.ReasonValidationFailed,
+				protectedLabels:     protectedLabels,
+				namespace:           &namespace,
+			}); statusErr != nil {
+				logger.Error(statusErr, "Failed to update NamespaceLabel status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
 	if err := finalizer.EnsureFinalizer(ctx, r.Client, &nsLabel); err != nil {
 		logger.Error(err, "unable to add finalizer")
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	if err := r.updateNamespaceLabels(ctx, req, namespaceLabelList, namespace, protectedPrefixes); err != nil {
+	sync := labelSync{
+		rejectedLabels:      rejectedLabels,
+		rejectedAnnotations: rejectedAnnotations,
+		failureReason:       namespacelabelv1alpha1.ReasonApplyFailed,
+		protectedLabels:     protectedLabels,
+		namespace:           &namespace,
+	}
+	if nsLabel.Spec.DryRun || r.DryRun {
+		sync.dryRun = true
+		sync.merge, sync.plan, sync.err = r.namespaceSyncer().dryRunNamespaceLabels(ctx, namespace, protectedLabels, nsLabel.Name)
+	} else {
+		sync.merge, sync.err = r.namespaceSyncer().updateNamespaceLabels(ctx, namespace, protectedLabels)
+	}
+	err = sync.err
+	if statusErr := r.updateStatus(ctx, &nsLabel, sync); statusErr != nil {
+		logger.Error(statusErr, "Failed to update NamespaceLabel status")
+		if err == nil {
+			err = statusErr
+		}
+	}
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{}, nil
 }
 
-// updateNamespaceLabels updates the labels of the namespace according to the NamespaceLabel resource.
-func (r *NamespaceLabelReconciler) updateNamespaceLabels(ctx context.Context, req ctrl.Request, namespaceLabelList namespacelabelv1alpha1.NamespaceLabelList, namespace corev1.Namespace, protectedPrefixes map[string]string) error {
-	logger := log.FromContext(ctx)
-	desiredLabels := make(map[string]string)
-	for _, label := range namespaceLabelList.Items {
-		for key, value := range label.Spec.Labels {
-			if !utils.IsReservedLabel(key, protectedPrefixes) {
-				desiredLabels[key] = value
-			}
+// handleDeletion handles the deletion of the NamespaceLabel object according to its deletion policy.
+// The NamespaceLabel being deleted is no longer a label source of the namespace, so its labels are
+// released by the sync. Keys with the Retain policy, set on the NamespaceLabel or on the key itself, and
+// every key of a NamespaceLabel with the Orphan policy are first handed over to the retainedFieldManager
+// so that they stay on the namespace.
+func (r *NamespaceLabelReconciler) handleDeletion(ctx context.Context, namespace corev1.Namespace, namespaceLabel namespacelabelv1alpha1.NamespaceLabel, protectedLabels *policy.ProtectedLabels, logger logr.Logger) error {
+	var labels, annotations map[string]string
+	if namespaceLabel.Spec.DeletionPolicy == namespacelabelv1alpha1.DeletionPolicyOrphan {
+		logger.Info("Orphaning the namespace labels of the deleted NamespaceLabel")
+		labels, annotations = namespaceLabel.Status.AppliedLabels, namespaceLabel.Status.AppliedAnnotations
+	} else {
+		keyPolicies, err := namespaceLabel.KeyDeletionPolicies()
+		if err != nil {
+			logger.Error(err, "Ignoring the key deletion policies of the deleted NamespaceLabel")
 		}
+		labels = retainedKeys(namespaceLabel.Status.AppliedLabels, keyPolicies.Labels, namespaceLabel.Spec.DeletionPolicy)
+		annotations = retainedKeys(namespaceLabel.Status.AppliedAnnotations, keyPolicies.Annotations, namespaceLabel.Spec.DeletionPolicy)
 	}
-
-	updatedLabels := make(map[string]string)
-	for key, value := range namespace.Labels {
-		updatedLabels[key] = value
-	}
-
-	for key := range updatedLabels {
-		if !utils.IsReservedLabel(key, protectedPrefixes) {
-			if _, exists := desiredLabels[key]; !exists {
-				delete(updatedLabels, key)
-			}
+	if len(labels) > 0 || len(annotations) > 0 {
+		if err := r.namespaceSyncer().retainKeys(ctx, namespace, labels, annotations); err != nil {
+			logger.Error(err, "Failed to retain the namespace labels of the deleted NamespaceLabel")
+			return err
 		}
 	}
-
-	for k, v := range desiredLabels {
-		if !utils.IsReservedLabel(k, protectedPrefixes) {
-			updatedLabels[k] = v
-		}
+	if _, err := r.namespaceSyncer().updateNamespaceLabels(ctx, namespace, protectedLabels); err != nil {
+		logger.Error(err, "Failed to remove deleted namespaces from the namespace")
+		return err
+	}
+	if err := finalizer.RemoveFinalizer(ctx, r.Client, &namespaceLabel); err != nil {
+		logger.Error(err, "Failed to remove finalizer")
+		return err
 	}
+	metrics.ForgetNamespaceLabel(namespaceLabel.Namespace, namespaceLabel.Name)
+	return nil
+}
 
-	if !utils.EqualLabels(updatedLabels, namespace.GetLabels()) {
-		namespace.Labels = updatedLabels
-		if err := r.Update(ctx, &namespace); err != nil {
-			logger.Error(err, "Failed to update NamespaceLabel")
-			return err
+// retainedKeys returns the applied keys to keep on the namespace, those whose own deletion policy, or the
+// deletion policy of their NamespaceLabel when they have none, is Retain.
+func retainedKeys(applied map[string]string, keyPolicies map[string]namespacelabelv1alpha1.DeletionPolicy, deletionPolicy namespacelabelv1alpha1.DeletionPolicy) map[string]string {
+	retained := make(map[string]string)
+	for key, value := range applied {
+		policy, exists := keyPolicies[key]
+		if !exists {
+			policy = deletionPolicy
+		}
+		if policy == namespacelabelv1alpha1.DeletionPolicyRetain {
+			retained[key] = value
 		}
-		logger.Info("Updated Namespace Successfully", "namespace", req.Namespace)
-	} else {
-		logger.Info("Namespace label is already up to date no changes needed")
 	}
-	return nil
+	return retained
 }
 
-// handleDeletion handles the deletion of the NamespaceLabel object.
-func (r *NamespaceLabelReconciler) handleDeletion(ctx context.Context, req ctrl.Request, namespace corev1.Namespace, namespaceLabel namespacelabelv1alpha1.NamespaceLabel, protectedPrefixes map[string]string, logger logr.Logger) error {
-	namespaceLabelList := namespacelabelv1alpha1.NamespaceLabelList{}
-	if err := r.List(ctx, &namespaceLabelList, client.InNamespace(namespace.Name)); err != nil {
-		logger.Error(err, "Failed to fetch NamespaceLabels")
-		return err
+// releaseNamespaceLabel handles a NamespaceLabel whose namespace is terminating or gone. No labels are
+// written to the namespace, a NamespaceLabel being deleted has its finalizer removed right away so that
+// it does not block the deletion of the namespace, and the reason is recorded in an event and the status.
+func (r *NamespaceLabelReconciler) releaseNamespaceLabel(ctx context.Context, nsLabel *namespacelabelv1alpha1.NamespaceLabel, reason, message string) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Skipping label writes", "reason", reason, "message", message)
+	if r.Recorder != nil {
+		r.Recorder.Event(nsLabel, corev1.EventTypeNormal, reason, message)
 	}
 
-	for index, label := range namespaceLabelList.Items {
-		if label.Name == namespaceLabel.Name && label.Namespace == namespaceLabel.Namespace {
-			namespaceLabelList.Items[index] = namespaceLabelList.Items[len(namespaceLabelList.Items)-1]
-			namespaceLabelList.Items = namespaceLabelList.Items[:len(namespaceLabelList.Items)-1]
+	if !nsLabel.DeletionTimestamp.IsZero() {
+		if err := finalizer.RemoveFinalizer(ctx, r.Client, nsLabel); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to remove finalizer")
+			return err
 		}
+		metrics.ForgetNamespaceLabel(nsLabel.Namespace, nsLabel.Name)
+		return nil
 	}
 
-	if err := r.updateNamespaceLabels(ctx, req, namespaceLabelList, namespace, protectedPrefixes); err != nil {
-		logger.Error(err, "Failed to remove deleted namespaces from the namespace")
-		return err
-	}
-	if err := finalizer.RemoveFinalizer(ctx, r.Client, &namespaceLabel); err != nil {
-		logger.Error(err, "Failed to remove finalizer")
+	setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, reason, message)
+	if err := r.Status().Update(ctx, nsLabel); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to update NamespaceLabel status")
 		return err
 	}
 	return nil
 }
 
+// namespaceSyncer returns the namespaceSyncer configured for the reconciler.
+func (r *NamespaceLabelReconciler) namespaceSyncer() namespaceSyncer {
+	return namespaceSyncer{
+		Client:               r.Client,
+		conflictStrategy:     r.ConflictStrategy,
+		protectedLabelAction: r.ProtectedLabelAction,
+		recorder:             r.Recorder,
+		dryRun:               r.DryRun,
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *NamespaceLabelReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&namespacelabelv1alpha1.NamespaceLabel{}).
+		For(&namespacelabelv1alpha1.NamespaceLabel{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		Watches(&namespacelabelv1alpha1.NamespaceLabel{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceLabelsInNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceLabelsOfNamespace),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{},
+				namespaceTerminatingPredicate)),
+		).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.allNamespaceLabels),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.isProtectedLabelsConfigMap)),
+		).
+		Watches(&namespacelabelv1alpha1.ProtectedLabelPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.allNamespaceLabels),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(&namespacelabelv1alpha1.ClusterNamespaceLabel{},
+			handler.EnqueueRequestsFromMapFunc(r.allNamespaceLabels),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 }
+
+// namespaceTerminatingPredicate passes namespace updates starting the deletion of the namespace.
+var namespaceTerminatingPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+	},
+}
+
+// isProtectedLabelsConfigMap reports whether the object is the protected labels ConfigMap.
+func (r *NamespaceLabelReconciler) isProtectedLabelsConfigMap(obj client.Object) bool {
+	return client.ObjectKeyFromObject(obj) == r.ProtectedLabelsSource.ConfigMapKey()
+}
+
+// allNamespaceLabels returns requests for every NamespaceLabel in the cluster, so that changes to the
+// protected labels are applied everywhere and conflicts with ClusterNamespaceLabels are reported.
+func (r *NamespaceLabelReconciler) allNamespaceLabels(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.requestsForNamespace(ctx, metav1.NamespaceAll)
+}
+
+// namespaceLabelsInNamespace maps an object to requests for every NamespaceLabel in its namespace,
+// so that the status of sibling NamespaceLabels follows conflicts introduced or resolved by the object.
+func (r *NamespaceLabelReconciler) namespaceLabelsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsForNamespace(ctx, obj.GetNamespace())
+}
+
+// namespaceLabelsOfNamespace maps a Namespace to requests for every NamespaceLabel inside it,
+// so that labels changed on the Namespace directly are restored.
+func (r *NamespaceLabelReconciler) namespaceLabelsOfNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsForNamespace(ctx, obj.GetName())
+}
+
+// requestsForNamespace returns requests for every NamespaceLabel in the given namespace.
+func (r *NamespaceLabelReconciler) requestsForNamespace(ctx context.Context, namespace string) []reconcile.Request {
+	namespaceLabelList := namespacelabelv1alpha1.NamespaceLabelList{}
+	if err := r.List(ctx, &namespaceLabelList, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list NamespaceLabels", "namespace", namespace)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(namespaceLabelList.Items))
+	for _, label := range namespaceLabelList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: label.Name, Namespace: label.Namespace},
+		})
+	}
+	return requests
+}
@@ -18,20 +18,32 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/oshribelay/namespace-label/internal/controller/metrics"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
 	"github.com/oshribelay/namespace-label/internal/controller/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	corev1 "k8s.io/api/core/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	namespacelabelv1beta1 "github.com/oshribelay/namespace-label/api/v1beta1"
 )
 
 var (
@@ -135,6 +147,26 @@ var _ = Describe("NamespaceLabel Controller", func() {
 			}))
 		})
 
+		It("should report the applied labels and conditions in the status", func() {
+			By("waiting for the status to be populated")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.AppliedLabels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("verifying the conditions reflect a successful reconcile")
+			Expect(nsLabel.Status.ObservedGeneration).To(Equal(nsLabel.Generation))
+			Expect(nsLabel.Status.LastSyncedTimeStamp).NotTo(BeNil())
+			Expect(nsLabel.Status.RejectedLabels).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionApplied)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionInvalid)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionConflicted)).To(BeTrue())
+		})
+
 		It("should update the namespace labels when NamespaceLabel is updated", func() {
 			By("updating the NamespaceLabel resource")
 			updatedNsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
@@ -185,6 +217,927 @@ var _ = Describe("NamespaceLabel Controller", func() {
 			}, timeout, interval).ShouldNot(HaveKeyWithValue("testLabel", "test-a"), "namespace labels should have been deleted")
 		})
 
+		It("should apply the namespace labels with its own field manager", func() {
+			By("waiting for the label to be applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("verifying the label is owned by the field manager of the controller")
+			Expect(namespace.ManagedFields).To(ContainElement(SatisfyAll(
+				HaveField("Manager", fieldManager),
+				HaveField("Operation", metav1.ManagedFieldsOperationApply),
+				HaveField("FieldsV1.Raw", ContainSubstring(`"f:`+randomLabelKey+`"`)),
+			)))
+		})
+
+		It("should record events on the NamespaceLabel and the namespace", func() {
+			By("verifying the applied labels were recorded on the NamespaceLabel and the namespace")
+			Eventually(func() []string {
+				eventList := &corev1.EventList{}
+				if err := k8sClient.List(ctx, eventList, client.InNamespace("default")); err != nil {
+					return nil
+				}
+				var events []string
+				for _, event := range eventList.Items {
+					if event.Reason == namespacelabelv1alpha1.EventReasonLabelsApplied && strings.Contains(event.Message, randomLabelKey) {
+						events = append(events, event.InvolvedObject.Kind)
+					}
+				}
+				return events
+			}, timeout, interval).Should(ContainElements("NamespaceLabel", "Namespace"))
+		})
+
+		It("should report the managed labels of the namespace in metrics", func() {
+			Eventually(func() float64 {
+				return testutil.ToFloat64(metrics.ManagedLabels.WithLabelValues("default"))
+			}, timeout, interval).Should(BeNumerically(">=", 1))
+			Expect(testutil.ToFloat64(metrics.Conflicts.WithLabelValues("default", resourceName))).To(BeZero())
+		})
+
+		It("should apply and release namespace annotations", func() {
+			By("adding an annotation to the NamespaceLabel")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Annotations = map[string]string{"cost-center": "platform"}
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the annotation was applied and reported in the status")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Annotations
+			}, timeout, interval).Should(HaveKeyWithValue("cost-center", "platform"))
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.AppliedAnnotations
+			}, timeout, interval).Should(HaveKeyWithValue("cost-center", "platform"))
+			Expect(nsLabel.Status.AppliedLabelCount).To(Equal(len(nsLabel.Status.AppliedLabels) + 1))
+
+			By("removing the annotation from the NamespaceLabel")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Annotations = nil
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Annotations
+			}, timeout, interval).ShouldNot(HaveKey("cost-center"))
+		})
+
+		It("should not remove namespace labels it does not manage", func() {
+			By("adding a label to the namespace outside of any NamespaceLabel")
+			namespace := &corev1.Namespace{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return err
+				}
+				if namespace.Labels == nil {
+					namespace.Labels = map[string]string{}
+				}
+				namespace.Labels["foreign-label"] = "foreign"
+				return k8sClient.Update(ctx, namespace)
+			}, timeout, interval).Should(Succeed())
+
+			By("updating the NamespaceLabel resource")
+			updatedNsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, updatedNsLabel); err != nil {
+					return err
+				}
+				updatedNsLabel.Spec.Labels["managedLabel"] = "managed"
+				return k8sClient.Update(ctx, updatedNsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the managed label was applied and the foreign label was kept")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(And(
+				HaveKeyWithValue("managedLabel", "managed"),
+				HaveKeyWithValue("foreign-label", "foreign"),
+			), "foreign labels should not be removed from the namespace")
+			Expect(namespace.Annotations).To(HaveKey(managedLabelsAnnotation))
+			Expect(namespace.Annotations[managedLabelsAnnotation]).NotTo(ContainSubstring("foreign-label"))
+		})
+
+		It("should restore managed labels removed from the namespace", func() {
+			By("waiting for the label to be applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("removing the managed label from the namespace directly")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return err
+				}
+				delete(namespace.Labels, randomLabelKey)
+				return k8sClient.Update(ctx, namespace)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the controller restored the managed label")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue), "drifted label should be restored")
+		})
+
+		It("should restore managed labels changed on the namespace", func() {
+			By("waiting for the label to be applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+			driftCorrections := testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("default"))
+
+			By("changing the value of the managed label with a different field manager")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return err
+				}
+				namespace.Labels[randomLabelKey] = "changed-value"
+				return k8sClient.Update(ctx, namespace, client.FieldOwner("other-manager"))
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the controller restored the managed label and counted the drift")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue), "changed label should be restored")
+			Eventually(func() float64 {
+				return testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("default"))
+			}, timeout, interval).Should(BeNumerically(">", driftCorrections))
+
+			By("verifying the drift correction was recorded on the namespace and the NamespaceLabel")
+			Eventually(func() []string {
+				return eventMessages("Namespace", "default", namespacelabelv1alpha1.EventReasonDriftCorrected)
+			}, timeout, interval).Should(ContainElement(ContainSubstring(randomLabelKey)))
+			Eventually(func() []string {
+				return eventMessages("NamespaceLabel", resourceName, namespacelabelv1alpha1.EventReasonDriftCorrected)
+			}, timeout, interval).Should(ContainElement(ContainSubstring(randomLabelKey)))
+		})
+
+		It("should take back managed labels applied by another field manager", func() {
+			By("waiting for the label to be applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("forcing another value of the managed label with a different field manager")
+			data, err := json.Marshal(corev1ac.Namespace("default").WithLabels(map[string]string{randomLabelKey: "foreign-value"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Patch(ctx, namespace, client.RawPatch(types.ApplyPatchType, data),
+				client.FieldOwner("other-manager"), client.ForceOwnership)).To(Succeed())
+
+			By("verifying the controller took the managed label back")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue), "changed label should be restored")
+			applied, err := corev1ac.ExtractNamespace(namespace, fieldManager)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied.Labels).To(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+		})
+
+		It("should keep the value of the first created NamespaceLabel on conflicts", func() {
+			By("creating a second NamespaceLabel setting the same label to a different value")
+			conflictingResource := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourcePrefix + utils.GenerateRandomString(10),
+					Namespace: "default",
+				},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels: map[string]string{
+						randomLabelKey: "conflicting-value",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, conflictingResource)).To(Succeed())
+
+			By("verifying the losing NamespaceLabel reports the conflict and names the winner")
+			Eventually(func() *metav1.Condition {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(conflictingResource), conflictingResource); err != nil {
+					return nil
+				}
+				return meta.FindStatusCondition(conflictingResource.Status.Conditions, namespacelabelv1alpha1.ConditionConflicted)
+			}, timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Message", ContainSubstring(resourceName)),
+			))
+
+			By("verifying the namespace keeps the value of the first created NamespaceLabel")
+			namespace := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+			Expect(namespace.Labels).To(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("verifying the conflict was recorded on the NamespaceLabel and the namespace")
+			Eventually(func() []string {
+				return eventMessages("NamespaceLabel", conflictingResource.Name, namespacelabelv1alpha1.EventReasonLabelConflict)
+			}, timeout, interval).ShouldNot(BeEmpty())
+			Eventually(func() []string {
+				return eventMessages("Namespace", "default", namespacelabelv1alpha1.EventReasonLabelConflict)
+			}, timeout, interval).Should(ContainElement(ContainSubstring("NamespaceLabel " + conflictingResource.Name)))
+
+			Expect(k8sClient.Delete(ctx, conflictingResource)).To(Succeed())
+		})
+
+		It("should release labels that become protected when the protected labels ConfigMap changes", func() {
+			const newlyProtectedKey = "newly-protected.io/team"
+
+			By("adding a label that is not protected yet")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Labels[newlyProtectedKey] = "a"
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(newlyProtectedKey, "a"))
+
+			By("protecting the label prefix in the ConfigMap")
+			protectedLabels := &corev1.ConfigMap{}
+			protectedLabelsKey := types.NamespacedName{
+				Name:      "namespace-label-protected-labels",
+				Namespace: "namespace-label-system",
+			}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, protectedLabelsKey, protectedLabels); err != nil {
+					return err
+				}
+				protectedLabels.Data["newly-protected.io"] = ""
+				return k8sClient.Update(ctx, protectedLabels)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the label is reported as rejected and released by the controller")
+			Eventually(func() []namespacelabelv1alpha1.RejectedLabel {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.RejectedLabels
+			}, timeout, interval).Should(ContainElement(HaveField("Key", newlyProtectedKey)))
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return ""
+				}
+				return namespace.Annotations[managedLabelsAnnotation]
+			}, timeout, interval).ShouldNot(ContainSubstring(newlyProtectedKey))
+			Expect(namespace.Labels).To(HaveKeyWithValue(newlyProtectedKey, "a"), "retained labels should stay on the namespace")
+
+			By("restoring the ConfigMap")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, protectedLabelsKey, protectedLabels); err != nil {
+					return err
+				}
+				delete(protectedLabels.Data, "newly-protected.io")
+				return k8sClient.Update(ctx, protectedLabels)
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should fall back to the default protected labels when the protected labels ConfigMap is missing", func() {
+			By("deleting the protected labels ConfigMap")
+			protectedLabels := &corev1.ConfigMap{}
+			protectedLabelsKey := types.NamespacedName{
+				Name:      "namespace-label-protected-labels",
+				Namespace: "namespace-label-system",
+			}
+			Expect(k8sClient.Get(ctx, protectedLabelsKey, protectedLabels)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, protectedLabels)).To(Succeed())
+
+			By("verifying the NamespaceLabel reports the default protected labels are in use")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionDefaultProtectedLabels)
+			}, timeout, interval).Should(BeTrue())
+			Expect(meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(nsLabel.Status.AppliedLabels).To(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("recreating the ConfigMap")
+			Expect(createTestConfigMap()).To(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionFalse(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionDefaultProtectedLabels)
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should reject labels protected by a ProtectedLabelPolicy", func() {
+			By("creating a ProtectedLabelPolicy protecting the label key")
+			protectedLabelPolicy := &namespacelabelv1alpha1.ProtectedLabelPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy-" + utils.GenerateRandomString(10)},
+				Spec: namespacelabelv1alpha1.ProtectedLabelPolicySpec{
+					Description: "labels owned by the platform team",
+					Patterns: []namespacelabelv1alpha1.LabelPattern{
+						{Type: namespacelabelv1alpha1.PatternTypeGlob, Pattern: "policy-protected*"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, protectedLabelPolicy)).To(Succeed())
+
+			By("verifying the policy reports it is ready")
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(protectedLabelPolicy), protectedLabelPolicy); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(protectedLabelPolicy.Status.Conditions, namespacelabelv1alpha1.ConditionReady)
+			}, timeout, interval).Should(BeTrue())
+
+			By("adding a protected label to the NamespaceLabel")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Labels["policy-protected-team"] = "a"
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the label was rejected")
+			Eventually(func() []namespacelabelv1alpha1.RejectedLabel {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.RejectedLabels
+			}, timeout, interval).Should(ContainElement(HaveField("Key", "policy-protected-team")))
+
+			By("deleting the ProtectedLabelPolicy")
+			Expect(k8sClient.Delete(ctx, protectedLabelPolicy)).To(Succeed())
+		})
+
+		It("should reject labels that are not valid Kubernetes labels with a reason per key", func() {
+			By("adding labels with an invalid key and an invalid value to the NamespaceLabel")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Labels["bad key!"] = "value"
+				nsLabel.Spec.Labels["too-long"] = strings.Repeat("a", 70)
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying every invalid label was reported with its reason")
+			Eventually(func() []namespacelabelv1alpha1.RejectedLabel {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.RejectedLabels
+			}, timeout, interval).Should(ConsistOf(
+				SatisfyAll(HaveField("Key", "bad key!"), HaveField("Reason", namespacelabelv1alpha1.ReasonInvalidKey)),
+				SatisfyAll(HaveField("Key", "too-long"), HaveField("Reason", namespacelabelv1alpha1.ReasonInvalidValue)),
+			))
+			Expect(meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionInvalid)).To(BeTrue())
+
+			By("verifying the valid label is still applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+			Expect(namespace.Labels).To(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+			Expect(namespace.Labels).NotTo(HaveKey("too-long"))
+		})
+
+		It("should keep the last applied labels of a Strict NamespaceLabel with invalid labels", func() {
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.AppliedLabels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("updating the NamespaceLabel with a new valid label and a protected label")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Labels["strict-label"] = "value"
+				nsLabel.Spec.Labels["kubernetes.io/strict"] = "value"
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the NamespaceLabel was rejected as a whole")
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionFalse(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionApplied)
+			}, timeout, interval).Should(BeTrue())
+			namespace := &corev1.Namespace{}
+			Consistently(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, time.Second*3, interval).Should(SatisfyAll(
+				HaveKeyWithValue(randomLabelKey, randomLabelValue),
+				Not(HaveKey("strict-label")),
+			))
+
+			By("switching the NamespaceLabel to the BestEffort validation mode")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.ValidationMode = namespacelabelv1alpha1.ValidationModeBestEffort
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+
+			By("verifying the valid labels were applied and the protected one skipped")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("strict-label", "value"))
+			Expect(namespace.Labels).NotTo(HaveKey("kubernetes.io/strict"))
+			Eventually(func() []namespacelabelv1alpha1.RejectedLabel {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.RejectedLabels
+			}, timeout, interval).Should(ConsistOf(HaveField("Key", "kubernetes.io/strict")))
+			Expect(nsLabel.Status.AppliedLabels).To(HaveKeyWithValue("strict-label", "value"))
+			Expect(nsLabel.Status.AppliedLabelCount).To(Equal(len(nsLabel.Status.AppliedLabels) + len(nsLabel.Status.AppliedAnnotations)))
+			Expect(nsLabel.Status.RejectedLabelCount).To(Equal(1))
+
+			By("verifying the skipped labels were recorded in an event")
+			Eventually(func() []string {
+				eventList := &corev1.EventList{}
+				if err := k8sClient.List(ctx, eventList, client.InNamespace("default")); err != nil {
+					return nil
+				}
+				var reasons []string
+				for _, event := range eventList.Items {
+					if event.InvolvedObject.Name == resourceName {
+						reasons = append(reasons, event.Reason)
+					}
+				}
+				return reasons
+			}, timeout, interval).Should(ContainElement(namespacelabelv1alpha1.EventReasonLabelsSkipped))
+			Eventually(func() []string {
+				return eventMessages("Namespace", "default", namespacelabelv1alpha1.EventReasonLabelsSkipped)
+			}, timeout, interval).Should(ContainElement(ContainSubstring("NamespaceLabel " + resourceName)))
+		})
+
+		It("should plan the changes of a dry run NamespaceLabel without applying them", func() {
+			By("creating a dry run NamespaceLabel")
+			dryRunName := types.NamespacedName{Name: resourcePrefix + "dry-run", Namespace: "default"}
+			dryRunResource := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: dryRunName.Name, Namespace: dryRunName.Namespace},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels: map[string]string{"dry-run-key": "planned"},
+					DryRun: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, dryRunResource)).To(Succeed())
+
+			By("verifying the planned label is reported in the status")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, dryRunName, dryRunResource); err != nil || dryRunResource.Status.Plan == nil {
+					return nil
+				}
+				return dryRunResource.Status.Plan.Labels.Added
+			}, timeout, interval).Should(HaveKeyWithValue("dry-run-key", "planned"))
+			Expect(dryRunResource.Status.AppliedLabels).To(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(dryRunResource.Status.Conditions, namespacelabelv1alpha1.ConditionApplied)).To(BeTrue())
+
+			By("verifying the planned label was not applied to the namespace")
+			namespace := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+			Expect(namespace.Labels).NotTo(HaveKey("dry-run-key"))
+
+			By("disabling the dry run")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, dryRunName, dryRunResource); err != nil {
+					return err
+				}
+				dryRunResource.Spec.DryRun = false
+				return k8sClient.Update(ctx, dryRunResource)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("dry-run-key", "planned"))
+			Eventually(func() *namespacelabelv1alpha1.NamespaceLabelPlan {
+				if err := k8sClient.Get(ctx, dryRunName, dryRunResource); err != nil {
+					return nil
+				}
+				return dryRunResource.Status.Plan
+			}, timeout, interval).Should(BeNil())
+
+			By("deleting the NamespaceLabel")
+			Expect(k8sClient.Delete(ctx, dryRunResource)).To(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).ShouldNot(HaveKey("dry-run-key"))
+		})
+
+		It("should keep the applied labels of a suspended NamespaceLabel", func() {
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.AppliedLabels
+			}, timeout, interval).Should(HaveKeyWithValue(randomLabelKey, randomLabelValue))
+
+			By("suspending the NamespaceLabel and changing its labels")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Suspend = true
+				nsLabel.Spec.Labels = map[string]string{"suspended-label": "value"}
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(nsLabel.Status.Conditions, namespacelabelv1alpha1.ConditionSuspended)
+			}, timeout, interval).Should(BeTrue())
+
+			By("verifying the applied labels are kept and the new labels are not applied")
+			namespace := &corev1.Namespace{}
+			Consistently(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, time.Second*3, interval).Should(SatisfyAll(
+				HaveKeyWithValue(randomLabelKey, randomLabelValue),
+				Not(HaveKey("suspended-label")),
+			))
+
+			By("resuming the NamespaceLabel")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Suspend = false
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(SatisfyAll(
+				HaveKeyWithValue("suspended-label", "value"),
+				Not(HaveKey(randomLabelKey)),
+			))
+		})
+
+		It("should retain the labels of a deleted NamespaceLabel with the Retain deletion policy", func() {
+			By("creating a NamespaceLabel with the Retain deletion policy")
+			retainName := types.NamespacedName{Name: resourcePrefix + "retain", Namespace: "default"}
+			retainResource := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: retainName.Name, Namespace: retainName.Namespace},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels:         map[string]string{"retained-key": "kept"},
+					DeletionPolicy: namespacelabelv1alpha1.DeletionPolicyRetain,
+				},
+			}
+			Expect(k8sClient.Create(ctx, retainResource)).To(Succeed())
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("retained-key", "kept"))
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, retainName, retainResource); err != nil {
+					return nil
+				}
+				return retainResource.Status.AppliedLabels
+			}, timeout, interval).Should(HaveKey("retained-key"))
+
+			By("deleting the NamespaceLabel")
+			Expect(k8sClient.Delete(ctx, retainResource)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, retainName, retainResource))
+			}, timeout, interval).Should(BeTrue())
+
+			By("verifying the label is kept on the namespace but no longer managed")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return ""
+				}
+				return namespace.Annotations[managedLabelsAnnotation]
+			}, timeout, interval).ShouldNot(ContainSubstring("retained-key"))
+			Expect(namespace.Labels).To(HaveKeyWithValue("retained-key", "kept"))
+		})
+
+		It("should keep the labels of a deleted NamespaceLabel with the Orphan deletion policy", func() {
+			By("creating a NamespaceLabel with the Orphan deletion policy and a key with the Delete policy")
+			orphanName := types.NamespacedName{Name: resourcePrefix + "orphan", Namespace: "default"}
+			orphanResource := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        orphanName.Name,
+					Namespace:   orphanName.Namespace,
+					Annotations: map[string]string{namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation: `{"labels":{"orphaned-deleted-key":"Delete"}}`},
+				},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels:         map[string]string{"orphaned-key": "kept", "orphaned-deleted-key": "kept"},
+					DeletionPolicy: namespacelabelv1alpha1.DeletionPolicyOrphan,
+				},
+			}
+			Expect(k8sClient.Create(ctx, orphanResource)).To(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, orphanName, orphanResource); err != nil {
+					return nil
+				}
+				return orphanResource.Status.AppliedLabels
+			}, timeout, interval).Should(SatisfyAll(HaveKey("orphaned-key"), HaveKey("orphaned-deleted-key")))
+
+			By("deleting the NamespaceLabel")
+			Expect(k8sClient.Delete(ctx, orphanResource)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, orphanName, orphanResource))
+			}, timeout, interval).Should(BeTrue())
+
+			By("verifying the labels are kept on the namespace but no longer managed")
+			namespace := &corev1.Namespace{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return ""
+				}
+				return namespace.Annotations[managedLabelsAnnotation]
+			}, timeout, interval).ShouldNot(ContainSubstring("orphaned-"))
+			Expect(namespace.Labels).To(SatisfyAll(
+				HaveKeyWithValue("orphaned-key", "kept"),
+				HaveKeyWithValue("orphaned-deleted-key", "kept"),
+			))
+			applied, err := corev1ac.ExtractNamespace(namespace, fieldManager)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied.Labels).NotTo(HaveKey("orphaned-key"))
+
+			By("syncing the namespace for another NamespaceLabel")
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, typeNamespacedName, nsLabel); err != nil {
+					return err
+				}
+				nsLabel.Spec.Labels["after-orphan"] = "synced"
+				return k8sClient.Update(ctx, nsLabel)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("after-orphan", "synced"))
+			Consistently(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, time.Second*3, interval).Should(HaveKeyWithValue("orphaned-key", "kept"), "orphaned labels should survive later syncs")
+		})
+
+		It("should apply a v1beta1 NamespaceLabel and retain the keys with the Retain deletion policy", func() {
+			By("creating a v1beta1 NamespaceLabel with a retained label entry")
+			entriesName := types.NamespacedName{Name: resourcePrefix + "entries", Namespace: "default"}
+			entriesResource := &namespacelabelv1beta1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: entriesName.Name, Namespace: entriesName.Namespace},
+				Spec: namespacelabelv1beta1.NamespaceLabelSpec{
+					Labels: []namespacelabelv1beta1.LabelEntry{
+						{Key: "entry-deleted", Value: "gone"},
+						{Key: "entry-retained", Value: "kept", DeletionPolicy: namespacelabelv1beta1.KeyDeletionPolicyRetain},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, entriesResource)).To(Succeed())
+
+			By("verifying the entries are applied and served through v1alpha1")
+			namespace := &corev1.Namespace{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).Should(SatisfyAll(
+				HaveKeyWithValue("entry-deleted", "gone"),
+				HaveKeyWithValue("entry-retained", "kept"),
+			))
+			nsLabel := &namespacelabelv1alpha1.NamespaceLabel{}
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, entriesName, nsLabel); err != nil {
+					return nil
+				}
+				return nsLabel.Status.AppliedLabels
+			}, timeout, interval).Should(HaveLen(2))
+			Expect(nsLabel.Annotations).To(HaveKeyWithValue(namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation,
+				`{"labels":{"entry-retained":"Retain"}}`))
+
+			By("deleting the NamespaceLabel")
+			Expect(k8sClient.Delete(ctx, entriesResource)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, entriesName, entriesResource))
+			}, timeout, interval).Should(BeTrue())
+
+			By("verifying only the retained label is kept on the namespace")
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace); err != nil {
+					return nil
+				}
+				return namespace.Labels
+			}, timeout, interval).ShouldNot(HaveKey("entry-deleted"))
+			Expect(namespace.Labels).To(HaveKeyWithValue("entry-retained", "kept"))
+		})
+
+		It("should delete a NamespaceLabel with protected labels", func() {
+			By("creating a NamespaceLabel with a protected label")
+			protectedName := types.NamespacedName{Name: resourcePrefix + "protected-delete", Namespace: "default"}
+			protectedResource := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{Name: protectedName.Name, Namespace: protectedName.Namespace},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels: map[string]string{"kubernetes.io/protected-delete": "value"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, protectedResource)).To(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, protectedName, protectedResource); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(protectedResource.Status.Conditions, namespacelabelv1alpha1.ConditionInvalid)
+			}, timeout, interval).Should(BeTrue())
+
+			By("verifying the NamespaceLabel is deleted")
+			Expect(k8sClient.Delete(ctx, protectedResource)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, protectedName, protectedResource))
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should force finalize a NamespaceLabel whose labels cannot be released", func() {
+			now := metav1.Now()
+			live := namespaceAppliedBy("live", map[string]string{"team": "a"})
+			stuck := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "stuck",
+					Namespace:         live.Name,
+					DeletionTimestamp: &now,
+					Finalizers:        []string{"finalizer.namespacelabel.dana.io"},
+				},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels: map[string]string{"team": "a"},
+				},
+			}
+			var applies int
+			fakeClient := fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(live, stuck).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if patch.Type() == types.ApplyPatchType {
+						applies++
+						return fmt.Errorf("namespace %s cannot be updated", obj.GetName())
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+			reconciler := &NamespaceLabelReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+			By("verifying the NamespaceLabel is kept while its labels cannot be released")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(stuck)})
+			Expect(err).To(HaveOccurred())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(stuck), stuck)).To(Succeed())
+			Expect(stuck.Finalizers).To(ContainElement("finalizer.namespacelabel.dana.io"))
+
+			By("adding the force finalize annotation")
+			stuck.Annotations = map[string]string{namespacelabelv1alpha1.ForceFinalizeAnnotation: "true"}
+			Expect(fakeClient.Update(ctx, stuck)).To(Succeed())
+			applies = 0
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(stuck)})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the finalizer was removed without updating the namespace")
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(stuck), stuck))).To(BeTrue())
+			Expect(applies).To(BeZero())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(live), live)).To(Succeed())
+			Expect(live.Labels).To(HaveKeyWithValue("team", "a"))
+		})
+
+		It("should delete a NamespaceLabel when the protected labels cannot be loaded", func() {
+			now := metav1.Now()
+			live := namespaceAppliedBy("live", map[string]string{"team": "a"})
+			deleted := &namespacelabelv1alpha1.NamespaceLabel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "deleted",
+					Namespace:         live.Name,
+					DeletionTimestamp: &now,
+					Finalizers:        []string{"finalizer.namespacelabel.dana.io"},
+				},
+				Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+					Labels: map[string]string{"team": "a"},
+				},
+			}
+			fakeClient, appliedNamespaces := applyRecordingClient(live, deleted)
+			reconciler := &NamespaceLabelReconciler{
+				Client: fakeClient,
+				Scheme: fakeClient.Scheme(),
+				// the protected labels ConfigMap does not exist
+				ProtectedLabelsSource: policy.Source{RequireConfigMap: true},
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(deleted)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*appliedNamespaces).To(ContainElement(live.Name))
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(deleted), deleted))).To(BeTrue())
+		})
+
+		It("should release the finalizer when the namespace is gone or terminating", func() {
+			now := metav1.Now()
+			deleted := func(namespace string) *namespacelabelv1alpha1.NamespaceLabel {
+				return &namespacelabelv1alpha1.NamespaceLabel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "deleted",
+						Namespace:         namespace,
+						DeletionTimestamp: &now,
+						Finalizers:        []string{"finalizer.namespacelabel.dana.io"},
+					},
+					Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+						Labels: map[string]string{"team": "a"},
+					},
+				}
+			}
+
+			By("reconciling a deleted NamespaceLabel whose namespace is gone")
+			gone := deleted("gone")
+			fakeClient := fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(gone).Build()
+			reconciler := &NamespaceLabelReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gone)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(gone), gone))).To(BeTrue())
+
+			By("reconciling a deleted NamespaceLabel whose namespace is terminating")
+			terminating := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "terminating",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{"kubernetes"},
+				},
+			}
+			nsLabel := deleted(terminating.Name)
+			fakeClient = fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(terminating, nsLabel).Build()
+			reconciler.Client = fakeClient
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(nsLabel)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(nsLabel), nsLabel))).To(BeTrue())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(terminating), terminating)).To(Succeed())
+			Expect(terminating.Labels).NotTo(HaveKey("team"))
+		})
+
 		It("should not apply protected label updates to the namespace", func() {
 			By("creating the invalid NamespaceLabel object we expect the labels to not apply to the namespace")
 			invalidResource := &namespacelabelv1alpha1.NamespaceLabel{
@@ -214,6 +1167,15 @@ var _ = Describe("NamespaceLabel Controller", func() {
 			Eventually(func() map[string]string {
 				return invalidNamespace.Labels
 			}, timeout, interval).ShouldNot(HaveKeyWithValue("k8s.io", "test-invalid"), "protected label should not have been applied to the namespace")
+
+			By("verifying the protected label was reported as rejected")
+			Eventually(func() []namespacelabelv1alpha1.RejectedLabel {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: invalidResource.Name, Namespace: invalidResource.Namespace}, invalidResource); err != nil {
+					return nil
+				}
+				return invalidResource.Status.RejectedLabels
+			}, timeout, interval).Should(ContainElement(HaveField("Key", "k8s.io")))
+			Expect(meta.IsStatusConditionTrue(invalidResource.Status.Conditions, namespacelabelv1alpha1.ConditionInvalid)).To(BeTrue())
 			Eventually(func() bool {
 				err := k8sClient.Delete(ctx, invalidResource)
 				if err != nil {
@@ -224,3 +1186,56 @@ var _ = Describe("NamespaceLabel Controller", func() {
 		})
 	})
 })
+
+// applyRecordingClient returns a fake client holding the objects which accepts the server-side apply
+// patches the fake client does not support, and records the names of the objects they were sent to.
+func applyRecordingClient(objs ...client.Object) (client.Client, *[]string) {
+	applied := &[]string{}
+	fakeClient := fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() == types.ApplyPatchType {
+				*applied = append(*applied, obj.GetName())
+				return nil
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+	return fakeClient, applied
+}
+
+// namespaceAppliedBy returns a namespace carrying the labels as if they had been applied by the controller.
+func namespaceAppliedBy(name string, labels map[string]string) *corev1.Namespace {
+	fields := make(map[string]any, len(labels))
+	for key := range labels {
+		fields["f:"+key] = map[string]any{}
+	}
+	raw, err := json.Marshal(map[string]any{"f:metadata": map[string]any{"f:labels": fields}})
+	Expect(err).NotTo(HaveOccurred())
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: labels,
+		ManagedFields: []metav1.ManagedFieldsEntry{{
+			Manager:    fieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		}},
+	}}
+}
+
+// eventMessages returns the messages of the events with the given reason recorded on the object of the given
+// kind and name. The events of namespaces are recorded in the default namespace.
+func eventMessages(kind, name, reason string) []string {
+	eventList := &corev1.EventList{}
+	if err := k8sClient.List(ctx, eventList, client.InNamespace("default")); err != nil {
+		return nil
+	}
+	var messages []string
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind == kind && event.InvolvedObject.Name == name && event.Reason == reason {
+			messages = append(messages, event.Message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/metrics"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+)
+
+// labelSync is the outcome of reconciling the labels of a namespace for a single NamespaceLabel.
+type labelSync struct {
+	// merge holds the labels and annotations merged from every label source of the namespace,
+	// its values are nil when the merge was never computed.
+	merge namespaceMerge
+	// rejectedLabels are the labels of the NamespaceLabel that failed validation.
+	rejectedLabels []namespacelabelv1alpha1.RejectedLabel
+	// rejectedAnnotations are the annotations of the NamespaceLabel that failed validation.
+	rejectedAnnotations []namespacelabelv1alpha1.RejectedLabel
+	// err is set when the labels could not be validated or written to the namespace.
+	err error
+	// failureReason is the condition reason reported together with err.
+	failureReason string
+	// protectedLabels are the protected labels used for the sync, nil when they could not be loaded.
+	protectedLabels *policy.ProtectedLabels
+	// dryRun is set when the changes were planned without being written to the namespace.
+	dryRun bool
+	// plan holds the planned changes of a dry run.
+	plan *namespacelabelv1alpha1.NamespaceLabelPlan
+	// suspended is set when the reconciliation is suspended, the rest of the status is left untouched.
+	suspended bool
+	// namespace is the namespace of the NamespaceLabel, rejections and conflicts are recorded on it too.
+	// It is nil when the namespace was not fetched.
+	namespace *corev1.Namespace
+}
+
+// rejected returns every rejected label and annotation, labels first.
+func (s labelSync) rejected() []namespacelabelv1alpha1.RejectedLabel {
+	return append(slices.Clone(s.rejectedLabels), s.rejectedAnnotations...)
+}
+
+// updateStatus records the applied labels, rejected labels and conditions of the NamespaceLabel
+// and writes them through the status subresource.
+func (r *NamespaceLabelReconciler) updateStatus(ctx context.Context, nsLabel *namespacelabelv1alpha1.NamespaceLabel, sync labelSync) error {
+	previous := *nsLabel.Status.DeepCopy()
+	status := &nsLabel.Status
+	status.ObservedGeneration = nsLabel.Generation
+	if sync.suspended {
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionSuspended, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonSuspended,
+			"reconciliation is suspended, the labels applied last are kept on the namespace")
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonSuspended,
+			"reconciliation is suspended")
+		return r.Status().Update(ctx, nsLabel)
+	}
+	setCondition(nsLabel, namespacelabelv1alpha1.ConditionSuspended, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonActive,
+		"reconciliation is active")
+
+	now := metav1.Now()
+	status.LastSyncedTimeStamp = &now
+	status.RejectedLabels = sync.rejectedLabels
+	status.RejectedAnnotations = sync.rejectedAnnotations
+	status.Plan = sync.plan
+	rejected := sync.rejected()
+
+	var conflicts []string
+	if sync.merge.labels.Values != nil {
+		appliedLabels, labelConflicts := appliedKeys(nsLabel.Spec.Labels, sync.rejectedLabels, sync.merge.labels, "")
+		appliedAnnotations, annotationConflicts := appliedKeys(nsLabel.Spec.Annotations, sync.rejectedAnnotations, sync.merge.annotations, "annotation ")
+		conflicts = append(labelConflicts, annotationConflicts...)
+		// a dry run leaves the namespace, and so the applied keys, untouched
+		if !sync.dryRun {
+			status.AppliedLabels, status.AppliedAnnotations = appliedLabels, appliedAnnotations
+		}
+
+		if len(conflicts) > 0 {
+			setCondition(nsLabel, namespacelabelv1alpha1.ConditionConflicted, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonLabelConflict,
+				"labels in conflict with other label sources: "+strings.Join(conflicts, ", "))
+		} else {
+			setCondition(nsLabel, namespacelabelv1alpha1.ConditionConflicted, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonNoConflicts,
+				"no label conflicts with other label sources")
+		}
+	}
+
+	if len(rejected) > 0 {
+		var messages []string
+		if keys := rejectedKeys(sync.rejectedLabels); keys != "" {
+			messages = append(messages, "labels rejected: "+keys)
+		}
+		if keys := rejectedKeys(sync.rejectedAnnotations); keys != "" {
+			messages = append(messages, "annotations rejected: "+keys)
+		}
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionInvalid, metav1.ConditionTrue, rejected[0].Reason,
+			strings.Join(messages, "; "))
+	} else {
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionInvalid, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonValid,
+			"all labels are valid")
+	}
+
+	if sync.protectedLabels != nil {
+		configMap := r.ProtectedLabelsSource.ConfigMapKey()
+		if sync.protectedLabels.UsesDefaults() {
+			setCondition(nsLabel, namespacelabelv1alpha1.ConditionDefaultProtectedLabels, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonConfigMapNotFound,
+				fmt.Sprintf("ConfigMap %s not found, using the default protected labels %s",
+					configMap, policy.DefaultProtectedLabelsDescription()))
+		} else {
+			setCondition(nsLabel, namespacelabelv1alpha1.ConditionDefaultProtectedLabels, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonConfigMapFound,
+				fmt.Sprintf("protected labels are read from ConfigMap %s", configMap))
+		}
+	}
+
+	if sync.err != nil {
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionApplied, metav1.ConditionFalse, sync.failureReason, sync.err.Error())
+	} else if sync.dryRun {
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionApplied, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonDryRun,
+			fmt.Sprintf("dry run, planned changes to namespace %s: %s", nsLabel.Namespace, describePlan(sync.plan)))
+	} else {
+		message := fmt.Sprintf("%d labels and %d annotations applied to namespace %s",
+			len(status.AppliedLabels), len(status.AppliedAnnotations), nsLabel.Namespace)
+		if len(rejected) > 0 {
+			message += fmt.Sprintf(", %d invalid keys skipped", len(rejected))
+		}
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionApplied, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonLabelsApplied, message)
+	}
+
+	switch {
+	case sync.err != nil:
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, sync.failureReason, sync.err.Error())
+	case len(rejected) > 0:
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, rejected[0].Reason,
+			"some labels were rejected")
+	case len(conflicts) > 0:
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonLabelConflict,
+			"some labels are owned by another label source")
+	case sync.dryRun:
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionFalse, namespacelabelv1alpha1.ReasonDryRun,
+			"the changes are planned in status.plan but not applied")
+	default:
+		setCondition(nsLabel, namespacelabelv1alpha1.ConditionReady, metav1.ConditionTrue, namespacelabelv1alpha1.ReasonReconciled,
+			"all labels are applied to the namespace")
+	}
+
+	status.AppliedLabelCount = len(status.AppliedLabels) + len(status.AppliedAnnotations)
+	status.RejectedLabelCount = len(status.RejectedLabels) + len(status.RejectedAnnotations)
+
+	rejectedByReason := make(map[string]int)
+	for _, r := range rejected {
+		rejectedByReason[r.Reason]++
+	}
+	metrics.RecordNamespaceLabel(nsLabel.Namespace, nsLabel.Name, rejectedByReason, len(conflicts))
+
+	if err := r.Status().Update(ctx, nsLabel); err != nil {
+		return err
+	}
+	r.recordStatusEvents(nsLabel, previous, sync.namespace)
+	return nil
+}
+
+// appliedKeys returns the keys of spec that are applied to the namespace according to the merge, together
+// with a sorted description of every key whose value is provided by another label source.
+func appliedKeys(spec map[string]string, rejected []namespacelabelv1alpha1.RejectedLabel, merge resources.KeyMerge, describe string) (map[string]string, []string) {
+	rejectedKeys := make(map[string]bool, len(rejected))
+	for _, r := range rejected {
+		rejectedKeys[r.Key] = true
+	}
+
+	applied := make(map[string]string)
+	var conflicts []string
+	for key, value := range spec {
+		if rejectedKeys[key] {
+			continue
+		}
+		desired, exists := merge.Values[key]
+		switch {
+		case exists && desired == value:
+			applied[key] = value
+		case exists:
+			conflicts = append(conflicts, fmt.Sprintf("%s%s (won by %s)", describe, key, merge.Owners[key]))
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("%s%s (rejected, set to different values by several label sources)", describe, key))
+		}
+	}
+	sort.Strings(conflicts)
+	return applied, conflicts
+}
+
+// rejectedKeys returns the keys of the rejected labels or annotations, each followed by the reason of
+// its rejection, as a comma separated list.
+func rejectedKeys(rejected []namespacelabelv1alpha1.RejectedLabel) string {
+	keys := make([]string, 0, len(rejected))
+	for _, r := range rejected {
+		keys = append(keys, fmt.Sprintf("%s (%s)", r.Key, r.Reason))
+	}
+	return strings.Join(keys, ", ")
+}
+
+// setCondition sets a condition on the NamespaceLabel for its current generation.
+func setCondition(nsLabel *namespacelabelv1alpha1.NamespaceLabel, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&nsLabel.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: nsLabel.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,318 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/metrics"
+)
+
+const (
+	// ProtectedLabelsConfigMapName is the default name of the legacy ConfigMap holding protected label prefixes.
+	ProtectedLabelsConfigMapName = "namespace-label-protected-labels"
+	// ProtectedLabelsConfigMapNamespace is the default namespace of the legacy ConfigMap holding protected label prefixes.
+	ProtectedLabelsConfigMapNamespace = "namespace-label-system"
+)
+
+// DefaultProtectedLabels are protected when the protected labels ConfigMap does not exist.
+var DefaultProtectedLabels = v1alpha1.ProtectedLabelPolicySpec{
+	Description: "labels reserved by Kubernetes",
+	Prefixes:    []string{"kubernetes.io/", "k8s.io/"},
+	Patterns: []v1alpha1.LabelPattern{
+		{Type: v1alpha1.PatternTypeGlob, Pattern: "*.kubernetes.io/*"},
+	},
+}
+
+// DefaultProtectedAnnotations are always protected, they hold the bookkeeping of the controller and of kubectl.
+var DefaultProtectedAnnotations = v1alpha1.ProtectedLabelPolicySpec{
+	Description: "annotations reserved by the controller and kubectl",
+	Target:      v1alpha1.PolicyTargetAnnotations,
+	Prefixes:    []string{"namespacelabel.dana.io/", "kubectl.kubernetes.io/"},
+}
+
+// DefaultProtectedLabelsDescription lists the default protected prefixes and patterns for humans.
+func DefaultProtectedLabelsDescription() string {
+	protected := slices.Clone(DefaultProtectedLabels.Prefixes)
+	for _, pattern := range DefaultProtectedLabels.Patterns {
+		protected = append(protected, pattern.Pattern)
+	}
+	return strings.Join(protected, ", ")
+}
+
+// ProtectedLabels decides which label and annotation keys cannot be managed by NamespaceLabels.
+type ProtectedLabels struct {
+	rules           []rule
+	annotationRules []rule
+	defaults        bool
+}
+
+// UsesDefaults reports whether the default protected labels are used because the protected labels
+// ConfigMap does not exist.
+func (p *ProtectedLabels) UsesDefaults() bool {
+	return p.defaults
+}
+
+// rule is a compiled set of protected label keys together with the namespaces exempt from it.
+type rule struct {
+	// policy is the name of the ProtectedLabelPolicy the rule was compiled from, empty for the defaults
+	// and the legacy ConfigMap.
+	policy string
+	// matchAll protects every key, it is set for policies with patterns that fail to compile.
+	matchAll         bool
+	prefixes         []string
+	keys             map[string]bool
+	patterns         []*regexp.Regexp
+	exemptNamespaces []*regexp.Regexp
+}
+
+// IsProtected reports whether the label key is protected in the given namespace.
+func (p *ProtectedLabels) IsProtected(namespace, key string) bool {
+	return anyRuleProtects(p.rules, namespace, key)
+}
+
+// IsProtectedAnnotation reports whether the annotation key is protected in the given namespace.
+func (p *ProtectedLabels) IsProtectedAnnotation(namespace, key string) bool {
+	return anyRuleProtects(p.annotationRules, namespace, key)
+}
+
+// BrokenPolicy returns the name of the ProtectedLabelPolicy with invalid patterns protecting the label key
+// in the given namespace, or an empty string when the key is protected by a valid rule or not at all.
+func (p *ProtectedLabels) BrokenPolicy(namespace, key string) string {
+	return brokenRuleProtecting(p.rules, namespace, key)
+}
+
+// BrokenAnnotationPolicy returns the name of the ProtectedLabelPolicy with invalid patterns protecting
+// the annotation key in the given namespace, or an empty string when the key is protected by a valid rule
+// or not at all.
+func (p *ProtectedLabels) BrokenAnnotationPolicy(namespace, key string) string {
+	return brokenRuleProtecting(p.annotationRules, namespace, key)
+}
+
+func brokenRuleProtecting(rules []rule, namespace, key string) string {
+	broken := ""
+	for _, r := range rules {
+		if !r.matches(key) || r.exempts(namespace) {
+			continue
+		}
+		if !r.matchAll {
+			return ""
+		}
+		if broken == "" {
+			broken = r.policy
+		}
+	}
+	return broken
+}
+
+func anyRuleProtects(rules []rule, namespace, key string) bool {
+	for _, r := range rules {
+		if r.matches(key) && !r.exempts(namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) matches(key string) bool {
+	if r.matchAll || r.keys[key] {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) exempts(namespace string) bool {
+	for _, exempt := range r.exemptNamespaces {
+		if exempt.MatchString(namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewProtectedLabels builds the protected labels and annotations from legacy ConfigMap label prefixes,
+// ProtectedLabelPolicies and DefaultProtectedAnnotations.
+// A policy with a pattern that fails to compile protects every key of its target outside its exempt
+// namespaces, BrokenPolicy names it and Validate reports its patterns.
+func NewProtectedLabels(prefixes map[string]string, policies []v1alpha1.ProtectedLabelPolicy) *ProtectedLabels {
+	defaultAnnotations, _ := compile(DefaultProtectedAnnotations)
+	protected := &ProtectedLabels{annotationRules: []rule{defaultAnnotations}}
+	if len(prefixes) > 0 {
+		r := rule{}
+		for prefix := range prefixes {
+			r.prefixes = append(r.prefixes, prefix)
+		}
+		protected.rules = append(protected.rules, r)
+	}
+	for _, policy := range policies {
+		r, _ := compile(policy.Spec)
+		r.policy = policy.Name
+		if policy.Spec.Target == v1alpha1.PolicyTargetAnnotations {
+			protected.annotationRules = append(protected.annotationRules, r)
+		} else {
+			protected.rules = append(protected.rules, r)
+		}
+	}
+	return protected
+}
+
+// Validate returns an error describing every pattern of the policy that cannot be compiled.
+func Validate(spec v1alpha1.ProtectedLabelPolicySpec) error {
+	_, errs := compile(spec)
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidatePattern returns an error when the pattern cannot be compiled.
+func ValidatePattern(pattern v1alpha1.LabelPattern) error {
+	_, err := compilePattern(pattern)
+	return err
+}
+
+// compile compiles the policy into a rule and returns the patterns that fail to compile. The rule of a
+// policy with invalid patterns fails closed and protects every key, so that a broken policy never makes
+// the keys it was meant to protect writable.
+func compile(spec v1alpha1.ProtectedLabelPolicySpec) (rule, []error) {
+	var errs []error
+	r := rule{
+		prefixes: spec.Prefixes,
+		keys:     make(map[string]bool, len(spec.Keys)),
+	}
+	for _, key := range spec.Keys {
+		r.keys[key] = true
+	}
+	for _, pattern := range spec.Patterns {
+		compiled, err := compilePattern(pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.patterns = append(r.patterns, compiled)
+	}
+	r.matchAll = len(errs) > 0
+	for _, namespace := range spec.ExemptNamespaces {
+		r.exemptNamespaces = append(r.exemptNamespaces, regexp.MustCompile(globToRegexp(namespace)))
+	}
+	return r, errs
+}
+
+// compilePattern compiles a glob or an anchored regular expression.
+func compilePattern(pattern v1alpha1.LabelPattern) (*regexp.Regexp, error) {
+	var expr string
+	switch pattern.Type {
+	case v1alpha1.PatternTypeRegex:
+		expr = "^(?:" + pattern.Pattern + ")$"
+	default:
+		expr = globToRegexp(pattern.Pattern)
+	}
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern.Pattern, err)
+	}
+	return compiled, nil
+}
+
+// globToRegexp converts a glob, where '*' matches any sequence of characters and '?' a single
+// character, into an anchored regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, c := range glob {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Source describes where the protected labels are loaded from.
+type Source struct {
+	// RequireConfigMap makes a missing protected labels ConfigMap an error instead of
+	// falling back to DefaultProtectedLabels.
+	RequireConfigMap bool
+
+	// ConfigMap is the protected labels ConfigMap. Empty fields default to ProtectedLabelsConfigMapName
+	// and ProtectedLabelsConfigMapNamespace.
+	ConfigMap types.NamespacedName
+}
+
+// ConfigMapKey returns the namespaced name of the protected labels ConfigMap.
+func (s Source) ConfigMapKey() types.NamespacedName {
+	key := s.ConfigMap
+	if key.Name == "" {
+		key.Name = ProtectedLabelsConfigMapName
+	}
+	if key.Namespace == "" {
+		key.Namespace = ProtectedLabelsConfigMapNamespace
+	}
+	return key
+}
+
+// ConfigMapCache restricts the ConfigMaps cached by a manager to the protected labels ConfigMap, the only
+// ConfigMap read and watched by the controllers.
+func (s Source) ConfigMapCache() cache.ByObject {
+	key := s.ConfigMapKey()
+	return cache.ByObject{
+		Namespaces: map[string]cache.Config{key.Namespace: {}},
+		Field:      fields.OneTermEqualSelector("metadata.name", key.Name),
+	}
+}
+
+// Load builds the protected labels from the protected labels ConfigMap and every ProtectedLabelPolicy in the cluster.
+func (s Source) Load(ctx context.Context, c client.Reader) (*ProtectedLabels, error) {
+	policyList := v1alpha1.ProtectedLabelPolicyList{}
+	if err := c.List(ctx, &policyList); err != nil {
+		metrics.ProtectedLabelsFetchFailures.WithLabelValues(metrics.SourcePolicy).Inc()
+		return nil, err
+	}
+
+	protectedLabelsConfigMap := corev1.ConfigMap{}
+	if err := c.Get(ctx, s.ConfigMapKey(), &protectedLabelsConfigMap); err != nil {
+		if !apierrors.IsNotFound(err) || s.RequireConfigMap {
+			metrics.ProtectedLabelsFetchFailures.WithLabelValues(metrics.SourceConfigMap).Inc()
+			return nil, err
+		}
+		return withDefaults(NewProtectedLabels(nil, policyList.Items)), nil
+	}
+	return NewProtectedLabels(protectedLabelsConfigMap.Data, policyList.Items), nil
+}
+
+// Defaults returns the DefaultProtectedLabels and DefaultProtectedAnnotations alone, for callers that
+// must make progress when the protected labels cannot be loaded.
+func Defaults() *ProtectedLabels {
+	return withDefaults(NewProtectedLabels(nil, nil))
+}
+
+// withDefaults adds the DefaultProtectedLabels to the protected labels.
+func withDefaults(protected *ProtectedLabels) *ProtectedLabels {
+	defaults, _ := compile(DefaultProtectedLabels)
+	protected.rules = append(protected.rules, defaults)
+	protected.defaults = true
+	return protected
+}
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+var _ = Describe("ProtectedLabels", func() {
+	var protectedLabels *ProtectedLabels
+
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	BeforeEach(func() {
+		protectedLabels = NewProtectedLabels(
+			map[string]string{"kubernetes.io/": ""},
+			[]v1alpha1.ProtectedLabelPolicy{{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+				Spec: v1alpha1.ProtectedLabelPolicySpec{
+					Prefixes: []string{"platform.dana.io/"},
+					Keys:     []string{"istio-injection"},
+					Patterns: []v1alpha1.LabelPattern{
+						{Type: v1alpha1.PatternTypeGlob, Pattern: "*.openshift.io/*"},
+						{Type: v1alpha1.PatternTypeRegex, Pattern: `pod-security\.kubernetes\.io/(enforce|warn)`},
+					},
+					ExemptNamespaces: []string{"platform-*"},
+				},
+			}},
+		)
+	})
+
+	It("should protect ConfigMap prefixes in every namespace", func() {
+		Expect(protectedLabels.IsProtected("platform-system", "kubernetes.io/metadata.name")).To(BeTrue())
+	})
+
+	It("should protect prefixes, exact keys and patterns of a policy", func() {
+		Expect(protectedLabels.IsProtected("default", "platform.dana.io/team")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "istio-injection")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "istio-injection-extra")).To(BeFalse())
+		Expect(protectedLabels.IsProtected("default", "apps.openshift.io/owner")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "pod-security.kubernetes.io/warn")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "pod-security.kubernetes.io/audit")).To(BeFalse())
+		Expect(protectedLabels.IsProtected("default", "team")).To(BeFalse())
+	})
+
+	It("should not apply a policy to its exempt namespaces", func() {
+		Expect(protectedLabels.IsProtected("platform-system", "platform.dana.io/team")).To(BeFalse())
+		Expect(protectedLabels.IsProtected("platform-system", "istio-injection")).To(BeFalse())
+	})
+
+	It("should protect annotations separately from labels", func() {
+		protectedLabels = NewProtectedLabels(nil, []v1alpha1.ProtectedLabelPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "annotations"},
+			Spec: v1alpha1.ProtectedLabelPolicySpec{
+				Target:   v1alpha1.PolicyTargetAnnotations,
+				Prefixes: []string{"openshift.io/"},
+			},
+		}})
+		Expect(protectedLabels.IsProtectedAnnotation("default", "openshift.io/node-selector")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "openshift.io/node-selector")).To(BeFalse())
+		Expect(protectedLabels.IsProtectedAnnotation("default", "namespacelabel.dana.io/managed-labels")).To(BeTrue())
+		Expect(protectedLabels.IsProtectedAnnotation("default", "scheduler.alpha.kubernetes.io/node-selector")).To(BeFalse())
+	})
+
+	It("should fall back to the default protected labels when the ConfigMap is missing", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		protectedLabels, err := Source{}.Load(context.Background(), c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(protectedLabels.UsesDefaults()).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "kubernetes.io/metadata.name")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "k8s.io/team")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "node.kubernetes.io/role")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "team")).To(BeFalse())
+	})
+
+	It("should fail when the ConfigMap is missing and required", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := Source{RequireConfigMap: true}.Load(context.Background(), c)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should read the ConfigMap configured in the source", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "protected", Namespace: "operators"},
+			Data:       map[string]string{"example.com": ""},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+		source := Source{ConfigMap: types.NamespacedName{Name: "protected", Namespace: "operators"}}
+		protectedLabels, err := source.Load(context.Background(), c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(protectedLabels.UsesDefaults()).To(BeFalse())
+		Expect(protectedLabels.IsProtected("default", "example.com/team")).To(BeTrue())
+	})
+
+	It("should only cache the ConfigMap configured in the source", func() {
+		byObject := Source{ConfigMap: types.NamespacedName{Name: "protected", Namespace: "operators"}}.ConfigMapCache()
+		Expect(byObject.Namespaces).To(HaveLen(1))
+		Expect(byObject.Namespaces).To(HaveKey("operators"))
+		Expect(byObject.Field.Matches(fields.Set{"metadata.name": "protected"})).To(BeTrue())
+		Expect(byObject.Field.Matches(fields.Set{"metadata.name": "kube-root-ca.crt"})).To(BeFalse())
+	})
+
+	It("should report patterns that cannot be compiled", func() {
+		err := Validate(v1alpha1.ProtectedLabelPolicySpec{
+			Patterns: []v1alpha1.LabelPattern{{Type: v1alpha1.PatternTypeRegex, Pattern: "team-("}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("team-(")))
+	})
+
+	It("should protect every key of a policy whose patterns cannot be compiled", func() {
+		protectedLabels = NewProtectedLabels(nil, []v1alpha1.ProtectedLabelPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+			Spec: v1alpha1.ProtectedLabelPolicySpec{
+				Patterns:         []v1alpha1.LabelPattern{{Type: v1alpha1.PatternTypeRegex, Pattern: "team-("}},
+				ExemptNamespaces: []string{"platform-*"},
+			},
+		}})
+		Expect(protectedLabels.IsProtected("default", "team-a")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("default", "anything")).To(BeTrue())
+		Expect(protectedLabels.IsProtected("platform-system", "anything")).To(BeFalse())
+		Expect(protectedLabels.IsProtectedAnnotation("default", "anything")).To(BeFalse())
+	})
+
+	It("should name the broken policy only for keys no valid rule protects", func() {
+		protectedLabels = withDefaults(NewProtectedLabels(nil, []v1alpha1.ProtectedLabelPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+			Spec: v1alpha1.ProtectedLabelPolicySpec{
+				Patterns:         []v1alpha1.LabelPattern{{Type: v1alpha1.PatternTypeRegex, Pattern: "team-("}},
+				ExemptNamespaces: []string{"platform-*"},
+			},
+		}}))
+		Expect(protectedLabels.BrokenPolicy("default", "team-a")).To(Equal("broken"))
+		Expect(protectedLabels.BrokenPolicy("default", "kubernetes.io/metadata.name")).To(BeEmpty())
+		Expect(protectedLabels.BrokenPolicy("platform-system", "team-a")).To(BeEmpty())
+		Expect(protectedLabels.BrokenAnnotationPolicy("default", "team-a")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+)
+
+// ProtectedLabelPolicyReconciler reconciles a ProtectedLabelPolicy object
+type ProtectedLabelPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=protectedlabelpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=namespacelabel.dana.io,resources=protectedlabelpolicies/status,verbs=get;update;patch
+
+// Reconcile validates the patterns of the ProtectedLabelPolicy and reports the result in its status.
+// Enforcing the policy is done by the NamespaceLabel reconciler.
+func (r *ProtectedLabelPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	protectedLabelPolicy := namespacelabelv1alpha1.ProtectedLabelPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, &protectedLabelPolicy); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to fetch ProtectedLabelPolicy")
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               namespacelabelv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: protectedLabelPolicy.Generation,
+		Reason:             namespacelabelv1alpha1.ReasonPolicyValid,
+		Message:            "all patterns are valid",
+	}
+	if err := policy.Validate(protectedLabelPolicy.Spec); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = namespacelabelv1alpha1.ReasonInvalidPattern
+		condition.Message = err.Error() + ", the policy protects every key until its patterns are fixed"
+	}
+
+	protectedLabelPolicy.Status.ObservedGeneration = protectedLabelPolicy.Generation
+	meta.SetStatusCondition(&protectedLabelPolicy.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, &protectedLabelPolicy); err != nil {
+		logger.Error(err, "Failed to update ProtectedLabelPolicy status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ProtectedLabelPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&namespacelabelv1alpha1.ProtectedLabelPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,179 @@
+package resources
+
+import (
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+)
+
+// ConflictStrategy decides which value is applied when several NamespaceLabels or ClusterNamespaceLabels
+// set a label of the same namespace to different values.
+type ConflictStrategy string
+
+const (
+	// ConflictStrategyFirstCreated applies the value of the oldest NamespaceLabel.
+	ConflictStrategyFirstCreated ConflictStrategy = "FirstCreated"
+	// ConflictStrategyPriority applies the value of the NamespaceLabel with the highest spec.priority,
+	// falling back to the oldest one on equal priorities.
+	ConflictStrategyPriority ConflictStrategy = "Priority"
+	// ConflictStrategyReject applies none of the conflicting values.
+	ConflictStrategyReject ConflictStrategy = "Reject"
+)
+
+// ConflictStrategies lists all supported conflict strategies.
+var ConflictStrategies = []ConflictStrategy{ConflictStrategyFirstCreated, ConflictStrategyPriority, ConflictStrategyReject}
+
+// LabelSource is a resource declaring labels for a namespace, either a NamespaceLabel in the namespace
+// or a ClusterNamespaceLabel selecting it.
+type LabelSource struct {
+	// Owner identifies the resource, such as "NamespaceLabel team-a".
+	Owner             string
+	Labels            map[string]string
+	Annotations       map[string]string
+	Priority          int32
+	CreationTimestamp metav1.Time
+}
+
+// NamespaceLabelSources returns the label sources of the NamespaceLabels that are not being deleted.
+// Dry run NamespaceLabels and Strict NamespaceLabels with invalid labels or annotations provide the labels
+// and annotations they applied last instead of their spec. Suspended NamespaceLabels provide the current
+// value of the keys they applied last, so that they are neither updated nor restored.
+func NamespaceLabelSources(items []v1alpha1.NamespaceLabel, namespace corev1.Namespace, protectedLabels *policy.ProtectedLabels) []LabelSource {
+	sources := make([]LabelSource, 0, len(items))
+	for _, item := range items {
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		labels, annotations := item.Spec.Labels, item.Spec.Annotations
+		switch {
+		case item.Spec.Suspend:
+			labels = currentValues(item.Status.AppliedLabels, namespace.Labels)
+			annotations = currentValues(item.Status.AppliedAnnotations, namespace.Annotations)
+		case item.Spec.DryRun || (!item.Spec.BestEffort() && len(ValidateNamespaceLabel(namespace.Name, labels, annotations, protectedLabels)) > 0):
+			labels, annotations = item.Status.AppliedLabels, item.Status.AppliedAnnotations
+		}
+		sources = append(sources, LabelSource{
+			Owner:             NamespaceLabelOwner(item.Name),
+			Labels:            labels,
+			Annotations:       annotations,
+			Priority:          item.Spec.Priority,
+			CreationTimestamp: item.CreationTimestamp,
+		})
+	}
+	return sources
+}
+
+// currentValues returns the current value of the applied keys that are still set.
+func currentValues(applied, current map[string]string) map[string]string {
+	values := make(map[string]string, len(applied))
+	for key := range applied {
+		if value, exists := current[key]; exists {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// ClusterNamespaceLabelSources returns the label sources of the ClusterNamespaceLabels that are not
+// being deleted and select the namespace. ClusterNamespaceLabels with an invalid selector are skipped.
+func ClusterNamespaceLabelSources(items []v1alpha1.ClusterNamespaceLabel, namespace corev1.Namespace) []LabelSource {
+	sources := make([]LabelSource, 0, len(items))
+	for _, item := range items {
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if matches, err := MatchesNamespace(item.Spec.NamespaceSelector, namespace); err != nil || !matches {
+			continue
+		}
+		sources = append(sources, LabelSource{
+			Owner:             ClusterNamespaceLabelOwner(item.Name),
+			Labels:            item.Spec.Labels,
+			Annotations:       item.Spec.Annotations,
+			Priority:          item.Spec.Priority,
+			CreationTimestamp: item.CreationTimestamp,
+		})
+	}
+	return sources
+}
+
+// NamespaceLabelOwner returns the owner of the labels declared by the NamespaceLabel.
+func NamespaceLabelOwner(name string) string {
+	return "NamespaceLabel " + name
+}
+
+// ClusterNamespaceLabelOwner returns the owner of the labels declared by the ClusterNamespaceLabel.
+func ClusterNamespaceLabelOwner(name string) string {
+	return "ClusterNamespaceLabel " + name
+}
+
+// KeyMerge is the result of merging the labels or the annotations of every label source of a namespace.
+type KeyMerge struct {
+	// Values are the desired values of the namespace keys.
+	Values map[string]string
+	// Owners maps every desired key to the owner of the label source providing its value.
+	Owners map[string]string
+}
+
+// MergeLabels merges the labels of the given label sources of the namespace, skipping protected labels
+// and labels that are not valid Kubernetes labels. Conflicting values are resolved deterministically
+// according to the given strategy.
+func MergeLabels(namespace string, sources []LabelSource, protectedLabels *policy.ProtectedLabels, strategy ConflictStrategy) KeyMerge {
+	return mergeKeys(sources, strategy,
+		func(source LabelSource) map[string]string { return source.Labels },
+		func(key, value string) bool {
+			return protectedLabels.IsProtected(namespace, key) || len(LabelSyntaxErrors(key, value)) > 0
+		})
+}
+
+// MergeAnnotations merges the annotations of the given label sources of the namespace, skipping protected
+// and invalid annotations. Conflicting values are resolved like conflicting labels.
+func MergeAnnotations(namespace string, sources []LabelSource, protectedLabels *policy.ProtectedLabels, strategy ConflictStrategy) KeyMerge {
+	return mergeKeys(sources, strategy,
+		func(source LabelSource) map[string]string { return source.Annotations },
+		func(key, value string) bool {
+			return protectedLabels.IsProtectedAnnotation(namespace, key) || len(AnnotationSyntaxErrors(key, value)) > 0
+		})
+}
+
+// mergeKeys merges the keys returned by values for every label source, the first source in the
+// order of the strategy wins. Keys for which skip returns true are never merged.
+func mergeKeys(sources []LabelSource, strategy ConflictStrategy, values func(LabelSource) map[string]string, skip func(key, value string) bool) KeyMerge {
+	sorted := slices.Clone(sources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if strategy == ConflictStrategyPriority && sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		if !sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) {
+			return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+		}
+		return sorted[i].Owner < sorted[j].Owner
+	})
+
+	merge := KeyMerge{
+		Values: make(map[string]string),
+		Owners: make(map[string]string),
+	}
+	conflicting := make(map[string]bool)
+	for _, source := range sorted {
+		for key, value := range values(source) {
+			if skip(key, value) || conflicting[key] {
+				continue
+			}
+			current, exists := merge.Values[key]
+			if !exists {
+				merge.Values[key] = value
+				merge.Owners[key] = source.Owner
+			} else if current != value && strategy == ConflictStrategyReject {
+				conflicting[key] = true
+				delete(merge.Values, key)
+				delete(merge.Owners, key)
+			}
+		}
+	}
+	return merge
+}
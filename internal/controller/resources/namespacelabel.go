@@ -1,17 +1,172 @@
 package resources
 
 import (
-	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
-	"github.com/oshribelay/namespace-label/internal/controller/utils"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+)
+
+// ProtectedLabelAction decides what happens to labels managed by the controller once their key
+// becomes protected.
+type ProtectedLabelAction string
+
+const (
+	// ProtectedLabelActionRetain leaves the label on the namespace and stops managing it.
+	ProtectedLabelActionRetain ProtectedLabelAction = "Retain"
+	// ProtectedLabelActionRemove removes the label from the namespace.
+	ProtectedLabelActionRemove ProtectedLabelAction = "Remove"
 )
 
-func ValidateNamespaceLabel(labels, protectedPrefixes map[string]string) error {
-	for key := range labels {
-		if utils.IsReservedLabel(key, protectedPrefixes) {
-			return errors.New(fmt.Sprintf("Invalid label: reserved label cannot be modified: %s", key))
+// ProtectedLabelActions lists all supported protected label actions.
+var ProtectedLabelActions = []ProtectedLabelAction{ProtectedLabelActionRetain, ProtectedLabelActionRemove}
+
+// SyntaxError is a reason a label or annotation does not follow the Kubernetes syntax.
+type SyntaxError struct {
+	// Reason is ReasonInvalidKey or ReasonInvalidValue.
+	Reason string
+	// Message describes the violated syntax rule.
+	Message string
+}
+
+// LabelSyntaxErrors validates a label key as a qualified name with an optional DNS subdomain prefix
+// and the value as a label value of at most 63 characters.
+func LabelSyntaxErrors(key, value string) []SyntaxError {
+	return append(syntaxErrors(v1alpha1.ReasonInvalidKey, validation.IsQualifiedName(key)),
+		syntaxErrors(v1alpha1.ReasonInvalidValue, validation.IsValidLabelValue(value))...)
+}
+
+// AnnotationSyntaxErrors validates an annotation key like the API server does, annotation values
+// may hold any string.
+func AnnotationSyntaxErrors(key, _ string) []SyntaxError {
+	return syntaxErrors(v1alpha1.ReasonInvalidKey, validation.IsQualifiedName(strings.ToLower(key)))
+}
+
+func syntaxErrors(reason string, messages []string) []SyntaxError {
+	errs := make([]SyntaxError, 0, len(messages))
+	for _, message := range messages {
+		errs = append(errs, SyntaxError{Reason: reason, Message: message})
+	}
+	return errs
+}
+
+// ValidateNamespaceLabel validates the labels and annotations of a NamespaceLabel against the protected
+// labels and the Kubernetes syntax. Every invalid key is reported, sorted by field path, so that the
+// result is deterministic and can be rendered by the reconciler as well as by the webhook.
+func ValidateNamespaceLabel(namespace string, labels, annotations map[string]string, protectedLabels *policy.ProtectedLabels) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validateKeys(specPath.Child("labels"), labels,
+		labelProtection(namespace, protectedLabels, "reserved key cannot be modified"), LabelSyntaxErrors)
+	allErrs = append(allErrs, validateKeys(specPath.Child("annotations"), annotations,
+		annotationProtection(namespace, protectedLabels, "reserved key cannot be modified"), AnnotationSyntaxErrors)...)
+	if err := apimachineryvalidation.ValidateAnnotationsSize(annotations); err != nil {
+		allErrs = append(allErrs, field.TooLong(specPath.Child("annotations"), "", apimachineryvalidation.TotalAnnotationSizeLimitB))
+	}
+	SortErrors(allErrs)
+	return allErrs
+}
+
+// SortErrors sorts the errors by field path, keeping the order of errors reported for the same field.
+func SortErrors(allErrs field.ErrorList) {
+	sort.SliceStable(allErrs, func(i, j int) bool {
+		return allErrs[i].Field < allErrs[j].Field
+	})
+}
+
+// protection returns the rejection message of a protected key, or an empty string when the key is not protected.
+type protection func(key string) string
+
+// labelProtection rejects the protected labels of the namespace with the given message, naming the
+// ProtectedLabelPolicy when the label is only protected because the policy has invalid patterns.
+func labelProtection(namespace string, protectedLabels *policy.ProtectedLabels, message string) protection {
+	return func(key string) string {
+		if !protectedLabels.IsProtected(namespace, key) {
+			return ""
+		}
+		return protectedMessage(message, protectedLabels.BrokenPolicy(namespace, key))
+	}
+}
+
+// annotationProtection is labelProtection for annotations.
+func annotationProtection(namespace string, protectedLabels *policy.ProtectedLabels, message string) protection {
+	return func(key string) string {
+		if !protectedLabels.IsProtectedAnnotation(namespace, key) {
+			return ""
+		}
+		return protectedMessage(message, protectedLabels.BrokenAnnotationPolicy(namespace, key))
+	}
+}
+
+func protectedMessage(message, brokenPolicy string) string {
+	if brokenPolicy == "" {
+		return message
+	}
+	return fmt.Sprintf("%s, ProtectedLabelPolicy %s has invalid patterns and protects every key", message, brokenPolicy)
+}
+
+// validateKeys returns an error for every protected key and every syntax rule broken by a key or its value.
+func validateKeys(path *field.Path, values map[string]string, protected protection, validate func(key, value string) []SyntaxError) field.ErrorList {
+	var allErrs field.ErrorList
+	for key, value := range values {
+		keyPath := path.Key(key)
+		if message := protected(key); message != "" {
+			allErrs = append(allErrs, field.Forbidden(keyPath, message))
+			continue
+		}
+		for _, syntaxErr := range validate(key, value) {
+			if syntaxErr.Reason == v1alpha1.ReasonInvalidValue {
+				allErrs = append(allErrs, field.Invalid(keyPath, value, syntaxErr.Message))
+			} else {
+				allErrs = append(allErrs, field.Invalid(keyPath, key, syntaxErr.Message))
+			}
+		}
+	}
+	return allErrs
+}
+
+// RejectedLabels returns the labels that cannot be applied to the namespace, sorted by key.
+func RejectedLabels(namespace string, labels map[string]string, protectedLabels *policy.ProtectedLabels) []v1alpha1.RejectedLabel {
+	return rejectedKeys(labels, labelProtection(namespace, protectedLabels, "reserved label cannot be modified"), LabelSyntaxErrors)
+}
+
+// RejectedAnnotations returns the annotations that cannot be applied to the namespace, sorted by key.
+func RejectedAnnotations(namespace string, annotations map[string]string, protectedLabels *policy.ProtectedLabels) []v1alpha1.RejectedLabel {
+	return rejectedKeys(annotations, annotationProtection(namespace, protectedLabels, "reserved annotation cannot be modified"), AnnotationSyntaxErrors)
+}
+
+// rejectedKeys rejects protected keys and keys failing the syntax validation. A key breaking several
+// syntax rules is reported once, with the reason of the first rule and every message.
+func rejectedKeys(values map[string]string, protected protection, validate func(key, value string) []SyntaxError) []v1alpha1.RejectedLabel {
+	var rejected []v1alpha1.RejectedLabel
+	for key, value := range values {
+		if message := protected(key); message != "" {
+			rejected = append(rejected, v1alpha1.RejectedLabel{
+				Key:     key,
+				Reason:  v1alpha1.ReasonProtectedLabel,
+				Message: message,
+			})
+			continue
+		}
+		if errs := validate(key, value); len(errs) > 0 {
+			messages := make([]string, 0, len(errs))
+			for _, err := range errs {
+				messages = append(messages, err.Message)
+			}
+			rejected = append(rejected, v1alpha1.RejectedLabel{
+				Key:     key,
+				Reason:  errs[0].Reason,
+				Message: strings.Join(messages, "; "),
+			})
 		}
 	}
-	return nil
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Key < rejected[j].Key
+	})
+	return rejected
 }
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+)
+
+var _ = Describe("ValidateNamespaceLabel", func() {
+	protectedLabels := policy.NewProtectedLabels(map[string]string{"platform.dana.io/": ""}, []v1alpha1.ProtectedLabelPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+		Spec: v1alpha1.ProtectedLabelPolicySpec{
+			Patterns:         []v1alpha1.LabelPattern{{Type: v1alpha1.PatternTypeRegex, Pattern: "team-("}},
+			ExemptNamespaces: []string{"platform-*"},
+		},
+	}})
+
+	It("should name the broken policy protecting a key", func() {
+		allErrs := ValidateNamespaceLabel("default", map[string]string{"team": "a", "platform.dana.io/team": "a"}, nil, protectedLabels)
+		Expect(allErrs).To(HaveLen(2))
+		Expect(allErrs[0].Field).To(Equal("spec.labels[platform.dana.io/team]"))
+		Expect(allErrs[0].Detail).To(Equal("reserved key cannot be modified"))
+		Expect(allErrs[1].Field).To(Equal("spec.labels[team]"))
+		Expect(allErrs[1].Detail).To(ContainSubstring("ProtectedLabelPolicy broken has invalid patterns"))
+	})
+
+	It("should only fail closed for the target and the non-exempt namespaces of the broken policy", func() {
+		Expect(ValidateNamespaceLabel("platform-system", map[string]string{"team": "a"}, nil, protectedLabels)).To(BeEmpty())
+		Expect(RejectedAnnotations("default", map[string]string{"team": "a"}, protectedLabels)).To(BeEmpty())
+		Expect(RejectedLabels("default", map[string]string{"team": "a"}, protectedLabels)).To(ConsistOf(v1alpha1.RejectedLabel{
+			Key:     "team",
+			Reason:  v1alpha1.ReasonProtectedLabel,
+			Message: "reserved label cannot be modified, ProtectedLabelPolicy broken has invalid patterns and protects every key",
+		}))
+	})
+})
@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+// MatchesNamespace reports whether the namespace is selected by the namespace selector. A selector
+// without a label selector and names selects no namespace.
+func MatchesNamespace(selector v1alpha1.NamespaceSelector, namespace corev1.Namespace) (bool, error) {
+	if selectsNothing(selector) {
+		return false, nil
+	}
+	if selector.LabelSelector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return false, err
+		}
+		if !labelSelector.Matches(labels.Set(namespace.Labels)) {
+			return false, nil
+		}
+	}
+	if len(selector.Names) == 0 {
+		return true, nil
+	}
+	for _, name := range selector.Names {
+		matched, err := path.Match(name, namespace.Name)
+		if err != nil {
+			return false, fmt.Errorf("invalid namespace name pattern %q: %w", name, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ValidateNamespaceSelector returns an error when the namespace selector is empty, or when its label
+// selector or one of its name patterns is invalid.
+func ValidateNamespaceSelector(selector v1alpha1.NamespaceSelector) error {
+	if selectsNothing(selector) {
+		return errors.New("at least one of labelSelector or names must be set")
+	}
+	if selector.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector.LabelSelector); err != nil {
+			return err
+		}
+	}
+	for _, name := range selector.Names {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("invalid namespace name pattern %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// selectsNothing reports whether the selector has neither a label selector nor names.
+func selectsNothing(selector v1alpha1.NamespaceSelector) bool {
+	return selector.LabelSelector == nil && len(selector.Names) == 0
+}
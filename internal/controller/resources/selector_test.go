@@ -0,0 +1,42 @@
+package resources
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+var _ = Describe("NamespaceSelector", func() {
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"environment": "test"}},
+	}
+	kubeSystem := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+
+	It("should select no namespace with an empty selector", func() {
+		for _, ns := range []corev1.Namespace{namespace, kubeSystem} {
+			matches, err := MatchesNamespace(v1alpha1.NamespaceSelector{}, ns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(BeFalse())
+		}
+		Expect(ValidateNamespaceSelector(v1alpha1.NamespaceSelector{})).To(MatchError(ContainSubstring("labelSelector or names")))
+	})
+
+	It("should select namespaces by labels and names", func() {
+		selector := v1alpha1.NamespaceSelector{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "test"}},
+			Names:         []string{"team-*"},
+		}
+		Expect(ValidateNamespaceSelector(selector)).To(Succeed())
+		Expect(MatchesNamespace(selector, namespace)).To(BeTrue())
+		Expect(MatchesNamespace(selector, kubeSystem)).To(BeFalse())
+	})
+
+	It("should select every namespace with an explicitly empty label selector", func() {
+		selector := v1alpha1.NamespaceSelector{LabelSelector: &metav1.LabelSelector{}}
+		Expect(MatchesNamespace(selector, kubeSystem)).To(BeTrue())
+	})
+})
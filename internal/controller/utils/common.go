@@ -2,19 +2,10 @@ package utils
 
 import (
 	"math/rand"
+	"sort"
 	"strings"
 )
 
-// IsReservedLabel checks if a label has a protected prefix.
-func IsReservedLabel(label string, protectedPrefixes map[string]string) bool {
-	for prefix := range protectedPrefixes {
-		if strings.HasPrefix(label, prefix) {
-			return true
-		}
-	}
-	return false
-}
-
 // EqualLabels checks if two maps of labels are equal to each other.
 func EqualLabels(a, b map[string]string) bool {
 	if len(a) != len(b) {
@@ -30,6 +21,27 @@ func EqualLabels(a, b map[string]string) bool {
 	return true
 }
 
+// ParseManagedKeys parses a comma separated list of label keys into a set.
+func ParseManagedKeys(value string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// FormatManagedKeys returns the keys of the given labels as a sorted, comma separated list.
+func FormatManagedKeys(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
 const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
 
 // GenerateRandomString generates a random string of length n.
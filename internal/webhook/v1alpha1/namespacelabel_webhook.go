@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+)
+
+// log is for logging in this package.
+var namespacelabellog = logf.Log.WithName("namespacelabel-resource")
+
+// SetupNamespaceLabelWebhookWithManager registers the webhook for NamespaceLabel in the manager.
+func SetupNamespaceLabelWebhookWithManager(mgr ctrl.Manager, conflictStrategy resources.ConflictStrategy, protectedLabelsSource policy.Source) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&namespacelabelv1alpha1.NamespaceLabel{}).
+		WithValidator(&NamespaceLabelCustomValidator{
+			// the API reader bypasses the cache of the manager, validation reads the protected labels and the
+			// NamespaceLabels straight from the API server
+			Client:                mgr.GetAPIReader(),
+			ConflictStrategy:      conflictStrategy,
+			ProtectedLabelsSource: protectedLabelsSource,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-namespacelabel-dana-io-v1alpha1-namespacelabel,mutating=false,failurePolicy=fail,sideEffects=None,groups=namespacelabel.dana.io,resources=namespacelabels,verbs=create;update,versions=v1alpha1,name=vnamespacelabel-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// NamespaceLabelCustomValidator validates NamespaceLabel resources when they are created or updated.
+type NamespaceLabelCustomValidator struct {
+	Client client.Reader
+
+	// ConflictStrategy is the strategy used by the controller. Conflicting labels are denied with the
+	// Reject strategy and admitted with a warning otherwise.
+	ConflictStrategy resources.ConflictStrategy
+
+	// ProtectedLabelsSource configures how the protected labels are loaded.
+	ProtectedLabelsSource policy.Source
+}
+
+var _ webhook.CustomValidator = &NamespaceLabelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type NamespaceLabel.
+func (v *NamespaceLabelCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	namespacelabel, ok := obj.(*namespacelabelv1alpha1.NamespaceLabel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceLabel object but got %T", obj)
+	}
+	namespacelabellog.Info("Validation for NamespaceLabel upon creation", "name", namespacelabel.GetName())
+
+	return v.validateNamespaceLabel(ctx, namespacelabel)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type NamespaceLabel.
+func (v *NamespaceLabelCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldNamespacelabel, ok := oldObj.(*namespacelabelv1alpha1.NamespaceLabel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceLabel object for the oldObj but got %T", oldObj)
+	}
+	namespacelabel, ok := newObj.(*namespacelabelv1alpha1.NamespaceLabel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceLabel object for the newObj but got %T", newObj)
+	}
+	namespacelabellog.Info("Validation for NamespaceLabel upon update", "name", namespacelabel.GetName())
+
+	// metadata only updates, such as finalizer removal, must never be blocked
+	keyDeletionPolicies := namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation
+	if !namespacelabel.DeletionTimestamp.IsZero() || (equality.Semantic.DeepEqual(oldNamespacelabel.Spec, namespacelabel.Spec) &&
+		oldNamespacelabel.Annotations[keyDeletionPolicies] == namespacelabel.Annotations[keyDeletionPolicies]) {
+		return nil, nil
+	}
+	return v.validateNamespaceLabel(ctx, namespacelabel)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type NamespaceLabel.
+func (v *NamespaceLabelCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateNamespaceLabel rejects labels and annotations with a protected prefix and keys or values that
+// are not valid Kubernetes labels or annotations. Keys already set to a different value by another
+// NamespaceLabel in the namespace are rejected with the Reject conflict strategy and reported as
+// warnings otherwise. Every error is reported at once, sorted by field path. Invalid keys of a BestEffort
+// NamespaceLabel are reported as warnings. An invalid KeyDeletionPoliciesAnnotation is always rejected.
+func (v *NamespaceLabelCustomValidator) validateNamespaceLabel(ctx context.Context, namespacelabel *namespacelabelv1alpha1.NamespaceLabel) (admission.Warnings, error) {
+	protectedLabels, err := v.ProtectedLabelsSource.Load(ctx, v.Client)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unable to fetch protected labels: %w", err))
+	}
+
+	namespaceLabelList := namespacelabelv1alpha1.NamespaceLabelList{}
+	if err := v.Client.List(ctx, &namespaceLabelList, client.InNamespace(namespacelabel.Namespace)); err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unable to list NamespaceLabels: %w", err))
+	}
+
+	allErrs := resources.ValidateNamespaceLabel(namespacelabel.Namespace, namespacelabel.Spec.Labels, namespacelabel.Spec.Annotations, protectedLabels)
+	specPath := field.NewPath("spec")
+	labelWarnings, labelErrs := v.conflicts(namespacelabel, namespaceLabelList, specPath.Child("labels"),
+		func(spec namespacelabelv1alpha1.NamespaceLabelSpec) map[string]string { return spec.Labels },
+		func(key string) bool { return protectedLabels.IsProtected(namespacelabel.Namespace, key) })
+	annotationWarnings, annotationErrs := v.conflicts(namespacelabel, namespaceLabelList, specPath.Child("annotations"),
+		func(spec namespacelabelv1alpha1.NamespaceLabelSpec) map[string]string { return spec.Annotations },
+		func(key string) bool { return protectedLabels.IsProtectedAnnotation(namespacelabel.Namespace, key) })
+	var warnings admission.Warnings
+	if namespacelabel.Spec.BestEffort() {
+		// invalid keys of a BestEffort NamespaceLabel are skipped by the controller
+		for _, err := range allErrs {
+			warnings = append(warnings, fmt.Sprintf("%s, the key will be skipped", err.Error()))
+		}
+		allErrs = nil
+	}
+	if _, err := namespacelabel.KeyDeletionPolicies(); err != nil {
+		annotation := namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "annotations").Key(annotation),
+			namespacelabel.Annotations[annotation], err.Error()))
+	}
+	warnings = append(warnings, append(labelWarnings, annotationWarnings...)...)
+	allErrs = append(allErrs, append(labelErrs, annotationErrs...)...)
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	resources.SortErrors(allErrs)
+	return warnings, apierrors.NewInvalid(namespacelabelv1alpha1.GroupVersion.WithKind("NamespaceLabel").GroupKind(), namespacelabel.Name, allErrs)
+}
+
+// conflicts checks the unprotected labels or annotations returned by values, in key order, against the
+// values set by the other NamespaceLabels of the namespace.
+func (v *NamespaceLabelCustomValidator) conflicts(namespacelabel *namespacelabelv1alpha1.NamespaceLabel, namespaceLabelList namespacelabelv1alpha1.NamespaceLabelList, path *field.Path,
+	values func(namespacelabelv1alpha1.NamespaceLabelSpec) map[string]string, isProtected func(key string) bool) (admission.Warnings, field.ErrorList) {
+	spec := values(namespacelabel.Spec)
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	for _, key := range keys {
+		value := spec[key]
+		keyPath := path.Key(key)
+		if isProtected(key) {
+			continue
+		}
+		for _, other := range namespaceLabelList.Items {
+			if other.Name == namespacelabel.Name || !other.DeletionTimestamp.IsZero() {
+				continue
+			}
+			otherValue, exists := values(other.Spec)[key]
+			if !exists || otherValue == value {
+				continue
+			}
+			msg := fmt.Sprintf("already set to %q by NamespaceLabel %s", otherValue, other.Name)
+			if v.ConflictStrategy == resources.ConflictStrategyReject {
+				allErrs = append(allErrs, field.Forbidden(keyPath, msg))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s: %s, resolved using the %s conflict strategy",
+					keyPath, msg, v.conflictStrategy()))
+			}
+		}
+	}
+	return warnings, allErrs
+}
+
+// conflictStrategy returns the configured conflict strategy, defaulting to FirstCreated.
+func (v *NamespaceLabelCustomValidator) conflictStrategy() resources.ConflictStrategy {
+	if v.ConflictStrategy == "" {
+		return resources.ConflictStrategyFirstCreated
+	}
+	return v.ConflictStrategy
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+	"github.com/oshribelay/namespace-label/internal/controller/resources"
+)
+
+var _ = Describe("NamespaceLabel Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *namespacelabelv1alpha1.NamespaceLabel
+		existing  *namespacelabelv1alpha1.NamespaceLabel
+		validator NamespaceLabelCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		protectedLabels := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      policy.ProtectedLabelsConfigMapName,
+				Namespace: policy.ProtectedLabelsConfigMapNamespace,
+			},
+			Data: map[string]string{
+				"k8s.io":        "",
+				"kubernetes.io": "",
+			},
+		}
+		existing = &namespacelabelv1alpha1.NamespaceLabel{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+			Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+				Labels: map[string]string{"team": "a"},
+			},
+		}
+		obj = &namespacelabelv1alpha1.NamespaceLabel{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"},
+			Spec: namespacelabelv1alpha1.NamespaceLabelSpec{
+				Labels: map[string]string{"env": "prod"},
+			},
+		}
+		validator = NamespaceLabelCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(protectedLabels, existing).Build(),
+		}
+	})
+
+	Context("When creating or updating NamespaceLabel under Validating Webhook", func() {
+		It("Should admit creation of valid labels", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation of protected labels", func() {
+			obj.Spec.Labels["kubernetes.io/metadata.name"] = "other"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.labels[kubernetes.io/metadata.name]"))
+		})
+
+		It("Should deny creation of syntactically invalid labels", func() {
+			obj.Spec.Labels["bad key!"] = "value"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.labels[bad key!]"))
+		})
+
+		It("Should report every label with an invalid key or value at once", func() {
+			obj.Spec.Labels["bad key!"] = "value"
+			obj.Spec.Labels["too-long"] = strings.Repeat("a", 70)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.labels[bad key!]"))
+			Expect(err.Error()).To(ContainSubstring("spec.labels[too-long]"))
+			Expect(err.Error()).To(ContainSubstring("must be no more than 63 characters"))
+		})
+
+		It("Should report every invalid key sorted by field path", func() {
+			obj.Spec.Labels["kubernetes.io/b"] = "b"
+			obj.Spec.Labels["kubernetes.io/a"] = "a"
+			obj.Spec.Annotations = map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+			statusErr, ok := err.(*apierrors.StatusError)
+			Expect(ok).To(BeTrue())
+			var fields []string
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(Equal([]string{
+				"spec.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+				"spec.labels[kubernetes.io/a]",
+				"spec.labels[kubernetes.io/b]",
+			}))
+		})
+
+		It("Should admit invalid labels with warnings in the BestEffort validation mode", func() {
+			obj.Spec.ValidationMode = namespacelabelv1alpha1.ValidationModeBestEffort
+			obj.Spec.Labels["kubernetes.io/metadata.name"] = "other"
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.labels[kubernetes.io/metadata.name]")))
+		})
+
+		It("Should admit annotations and deny protected ones", func() {
+			obj.Spec.Annotations = map[string]string{"scheduler.alpha.kubernetes.io/node-selector": "env=prod"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Annotations["namespacelabel.dana.io/managed-labels"] = "env"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.annotations[namespacelabel.dana.io/managed-labels]"))
+		})
+
+		It("Should warn about labels owned by another NamespaceLabel with a different value", func() {
+			obj.Spec.Labels["team"] = "b"
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("NamespaceLabel existing")))
+		})
+
+		It("Should deny labels owned by another NamespaceLabel with the Reject conflict strategy", func() {
+			validator.ConflictStrategy = resources.ConflictStrategyReject
+			obj.Spec.Labels["team"] = "b"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("NamespaceLabel existing"))
+		})
+
+		It("Should admit labels set to the same value by another NamespaceLabel", func() {
+			obj.Spec.Labels["team"] = "a"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit updates that do not change the spec", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.Labels["k8s.io/invalid"] = "value"
+			oldObj.Spec.Labels["k8s.io/invalid"] = "value"
+			obj.Finalizers = []string{"finalizer.namespacelabel.dana.io"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny an invalid key deletion policies annotation", func() {
+			oldObj := obj.DeepCopy()
+			obj.Annotations = map[string]string{namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation: `{"labels":{"env":"Orphan"}}`}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("metadata.annotations[namespacelabel.dana.io/key-deletion-policies]"))
+
+			obj.Annotations[namespacelabelv1alpha1.KeyDeletionPoliciesAnnotation] = `{"labels":{"env":"Retain"}}`
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny updates adding protected labels", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.Labels["k8s.io/invalid"] = "value"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+})
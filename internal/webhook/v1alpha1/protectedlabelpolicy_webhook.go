@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+	"github.com/oshribelay/namespace-label/internal/controller/policy"
+)
+
+// log is for logging in this package.
+var protectedlabelpolicylog = logf.Log.WithName("protectedlabelpolicy-resource")
+
+// SetupProtectedLabelPolicyWebhookWithManager registers the webhook for ProtectedLabelPolicy in the manager.
+func SetupProtectedLabelPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&namespacelabelv1alpha1.ProtectedLabelPolicy{}).
+		WithValidator(&ProtectedLabelPolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-namespacelabel-dana-io-v1alpha1-protectedlabelpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=namespacelabel.dana.io,resources=protectedlabelpolicies,verbs=create;update,versions=v1alpha1,name=vprotectedlabelpolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ProtectedLabelPolicyCustomValidator validates ProtectedLabelPolicy resources when they are created or updated.
+type ProtectedLabelPolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ProtectedLabelPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ProtectedLabelPolicy.
+func (v *ProtectedLabelPolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	protectedlabelpolicy, ok := obj.(*namespacelabelv1alpha1.ProtectedLabelPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ProtectedLabelPolicy object but got %T", obj)
+	}
+	protectedlabelpolicylog.Info("Validation for ProtectedLabelPolicy upon creation", "name", protectedlabelpolicy.GetName())
+
+	return nil, validateProtectedLabelPolicy(protectedlabelpolicy)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ProtectedLabelPolicy.
+func (v *ProtectedLabelPolicyCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	protectedlabelpolicy, ok := newObj.(*namespacelabelv1alpha1.ProtectedLabelPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ProtectedLabelPolicy object for the newObj but got %T", newObj)
+	}
+	protectedlabelpolicylog.Info("Validation for ProtectedLabelPolicy upon update", "name", protectedlabelpolicy.GetName())
+
+	return nil, validateProtectedLabelPolicy(protectedlabelpolicy)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ProtectedLabelPolicy.
+func (v *ProtectedLabelPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateProtectedLabelPolicy rejects patterns that cannot be compiled.
+func validateProtectedLabelPolicy(protectedlabelpolicy *namespacelabelv1alpha1.ProtectedLabelPolicy) error {
+	var allErrs field.ErrorList
+	patternsPath := field.NewPath("spec", "patterns")
+	for i, pattern := range protectedlabelpolicy.Spec.Patterns {
+		if err := policy.ValidatePattern(pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(patternsPath.Index(i).Child("pattern"), pattern.Pattern, err.Error()))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(namespacelabelv1alpha1.GroupVersion.WithKind("ProtectedLabelPolicy").GroupKind(), protectedlabelpolicy.Name, allErrs)
+}
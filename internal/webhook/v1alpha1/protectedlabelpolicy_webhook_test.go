@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	namespacelabelv1alpha1 "github.com/oshribelay/namespace-label/api/v1alpha1"
+)
+
+var _ = Describe("ProtectedLabelPolicy Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *namespacelabelv1alpha1.ProtectedLabelPolicy
+		validator ProtectedLabelPolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &namespacelabelv1alpha1.ProtectedLabelPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec: namespacelabelv1alpha1.ProtectedLabelPolicySpec{
+				Patterns: []namespacelabelv1alpha1.LabelPattern{
+					{Type: namespacelabelv1alpha1.PatternTypeGlob, Pattern: "*.openshift.io/*"},
+					{Type: namespacelabelv1alpha1.PatternTypeRegex, Pattern: `team-(a|b)`},
+				},
+			},
+		}
+		validator = ProtectedLabelPolicyCustomValidator{}
+	})
+
+	Context("When creating or updating ProtectedLabelPolicy under Validating Webhook", func() {
+		It("Should admit valid patterns", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny patterns that cannot be compiled", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.Patterns = append(obj.Spec.Patterns,
+				namespacelabelv1alpha1.LabelPattern{Type: namespacelabelv1alpha1.PatternTypeRegex, Pattern: "team-("})
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.patterns[2].pattern"))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	namespacelabelv1beta1 "github.com/oshribelay/namespace-label/api/v1beta1"
+)
+
+// SetupNamespaceLabelWebhookWithManager registers the conversion webhook for NamespaceLabel in the manager.
+// The v1beta1 version is the conversion hub, the other versions implement conversion.Convertible.
+func SetupNamespaceLabelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&namespacelabelv1beta1.NamespaceLabel{}).
+		Complete()
+}